@@ -1,8 +1,10 @@
 package main
 
 import (
+	"covlet/pkg/cli"
 	"covlet/pkg/gui"
-	"log"
+	"fmt"
+	"os"
 )
 
 // Project represents a single project entry.
@@ -13,12 +15,11 @@ type Project struct {
 }
 
 func main() {
-	// err := cli.Run()
-	//if err != nil {
-	//	log.Fatal(err)
-	//}
-	err := gui.Run()
-	if err != nil {
-		log.Fatal(err)
+	// without a command, covlet opens the GUI
+	if err := cli.Run(os.Args, gui.Run); err != nil {
+		if msg := err.Error(); msg != "" {
+			fmt.Fprintln(os.Stderr, "covlet:", msg)
+		}
+		os.Exit(cli.ExitCode(err))
 	}
 }
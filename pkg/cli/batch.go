@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"covlet/pkg/internal"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// batchCommand renders one letter per row of a jobs file.
+func batchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "batch",
+		Usage: "Render one letter per application listed in a CSV or YAML jobs file",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "jobs",
+				Usage:    "CSV file with a header row, or YAML list; columns company, role, manager or any override path",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "template",
+				Aliases: []string{"t"},
+				Value:   defaultTemplate,
+				Usage:   "Template name relative to the templates directory (the extension may be left off), or a path",
+			},
+			&cli.StringSliceFlag{
+				Name:  "values",
+				Usage: "Values file layered over the resume for every job; repeatable, later files win",
+			},
+			&cli.StringSliceFlag{
+				Name:  "set",
+				Usage: "Override a value for every job, e.g. --set Name=Jane; job columns win; repeatable",
+			},
+			&cli.StringFlag{
+				Name:  "out-dir",
+				Value: ".",
+				Usage: "Directory for the rendered files",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Value: internal.DefaultBatchName,
+				Usage: "File name pattern (a template over the job's values and .Row); its extension picks the format (default: the template's output name, else this)",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: txt, md, html or pdf (default: from the --name extension)",
+			},
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "Title pattern for pdf and html output, e.g. \"Application to {{ .CompanyToApplyTo }}\"",
+			},
+			&cli.IntFlag{
+				Name:  "parallel",
+				Usage: "Letters rendered at once (default: one per CPU)",
+			},
+			siblingsFlag(),
+		},
+		Action: func(cCtx *cli.Context) error {
+			var format internal.Format
+			if f := cCtx.String("format"); f != "" {
+				var err error
+				if format, err = internal.ParseFormat(f); err != nil {
+					return cli.Exit(err.Error(), 2)
+				}
+			}
+			overrides, err := renderOverrides(cCtx)
+			if err != nil {
+				return cli.Exit(err.Error(), 2)
+			}
+			if err := resolveHome(cCtx); err != nil {
+				return err
+			}
+
+			jobs, err := internal.ReadJobsFile(cCtx.String("jobs"))
+			if err != nil {
+				return fmt.Errorf("error reading jobs: %w", err)
+			}
+			stack, err := loadStack(cCtx.String("config"), cCtx.StringSlice("values"))
+			if err != nil {
+				return err
+			}
+			stack, err = stack.WithOverrides(overrides)
+			if err != nil {
+				return fmt.Errorf("error applying overrides: %w", err)
+			}
+			letter, err := parseTemplate(cCtx)
+			if err != nil {
+				return err
+			}
+
+			// an unset --name leaves the choice to the template
+			var name string
+			if cCtx.IsSet("name") {
+				name = cCtx.String("name")
+			}
+			b := &internal.Batch{
+				Template: letter.Template,
+				Base:     stack.Resume,
+				OutDir:   cCtx.String("out-dir"),
+				Name:     name,
+				Format:   format,
+				Title:    cCtx.String("title"),
+				Parallel: cCtx.Int("parallel"),
+				Meta:     letter.Meta,
+			}
+			results, err := b.Run(jobs)
+			if err != nil {
+				return err
+			}
+			for _, r := range results {
+				if r.Err == nil {
+					fmt.Fprintln(cCtx.App.Writer, r.Path)
+				}
+			}
+			failed := internal.Failed(results)
+			for _, r := range failed {
+				fmt.Fprintf(cCtx.App.ErrWriter, "%s: %v\n", r.Job.Label(), r.Err)
+			}
+			summary := fmt.Sprintf("rendered %d of %d letters", len(results)-len(failed), len(results))
+			if len(failed) > 0 {
+				return cli.Exit(fmt.Sprintf("%s; %d failed", summary, len(failed)), 1)
+			}
+			fmt.Fprintln(cCtx.App.ErrWriter, summary)
+			return nil
+		},
+	}
+}
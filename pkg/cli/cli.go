@@ -2,73 +2,128 @@ package cli
 
 import (
 	"covlet/pkg/config"
+	"errors"
 	"fmt"
+
 	"github.com/urfave/cli/v2"
-	"log"
-	"os"
-	"text/template"
 )
 
-func Run() error {
+// Run runs covlet with the given command line, normally os.Args. Without a
+// command it opens the GUI by calling openGUI, which is passed in so this
+// package does not depend on the GUI toolkit. Errors are returned rather than
+// printed or exited on; see ExitCode.
+func Run(args []string, openGUI func() error) error {
+	return newApp(openGUI).Run(args)
+}
+
+// ExitCode returns the process exit code for an error returned by Run:
+// 0 for nil, the code of a cli.Exit error (2 for usage errors), otherwise 1.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var ec cli.ExitCoder
+	if errors.As(err, &ec) {
+		return ec.ExitCode()
+	}
+	return 1
+}
+
+func newApp(openGUI func() error) *cli.App {
+	runGUI := func(cCtx *cli.Context) error {
+		if err := resolveHome(cCtx); err != nil {
+			return err
+		}
+		if openGUI == nil {
+			return errors.New("the editor is not available in this build")
+		}
+		return openGUI()
+	}
 	app := &cli.App{
-		Name:  "cover-letter",
-		Usage: "Generate a cover letter from template",
+		Name:  "covlet",
+		Usage: "Write cover letters from templates and your resume values",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:     "company",
-				Aliases:  []string{"c"},
-				Usage:    "Company to apply to",
-				Required: false,
+				Name:  "home",
+				Usage: "Covlet main directory (default: $COVLET_HOME, then the last folder opened in the app)",
 			},
 			&cli.StringFlag{
-				Name:     "manager",
-				Aliases:  []string{"m"},
-				Usage:    "Hiring manager's name",
-				Required: false,
+				Name:  "config",
+				Usage: "Resume values file (default: $COVLET_CONFIG, then values/resume.yml in the main directory)",
 			},
 			&cli.StringFlag{
-				Name:     "position",
-				Aliases:  []string{"p"},
-				Usage:    "Position to apply for",
-				Required: false,
+				Name:  "profile",
+				Usage: "Named profile under values/profiles (default: the profile last chosen in the app)",
+			},
+			&cli.StringFlag{
+				Name:    "passphrase-file",
+				Usage:   "File whose first line is the passphrase for encrypted values files",
+				EnvVars: []string{"COVLET_PASSPHRASE_FILE"},
 			},
 		},
+		Commands: []*cli.Command{
+			renderCommand(),
+			batchCommand(),
+			exportCommand(),
+			listCommand(),
+			validateCommand(),
+			newCommand(),
+			guiCommand(runGUI),
+			importCommand(),
+			encryptCommand(),
+			decryptCommand(),
+		},
 		Action: func(cCtx *cli.Context) error {
-			configFile, err := config.LoadConfig("config.yml")
-			if err != nil {
-				return fmt.Errorf("error loading config: %v", err)
+			if cCtx.Args().Present() {
+				return cli.Exit(fmt.Sprintf("unknown command %q (see covlet --help)", cCtx.Args().First()), 2)
 			}
-
-			if company := cCtx.String("company"); company != "" {
-				configFile.Resume.CompanyToApplyTo = company
-			}
-			if position := cCtx.String("position"); position != "" {
-				configFile.Resume.RoleToApplyTo = position
-			}
-
-			templateFile, err := os.ReadFile("templates/base/cover_letter.tpl")
-			if err != nil {
-				return fmt.Errorf("error reading template file: %v", err)
-			}
-
-			t, err := template.New("cover_letter").Parse(string(templateFile))
-			if err != nil {
-				return fmt.Errorf("error parsing template: %v", err)
-			}
-
-			fmt.Println("--- Generated Cover Letter ---")
-			err = t.Execute(os.Stdout, configFile.Resume)
-			if err != nil {
-				return fmt.Errorf("error executing template: %v", err)
-			}
-
-			return nil
+			return runGUI(cCtx)
 		},
+		OnUsageError: usageError,
+		// --set values may contain commas, e.g. --set Skills=Go,SQL
+		DisableSliceFlagSeparator: true,
+		// errors are returned to main, which prints them and picks the exit code
+		ExitErrHandler: func(*cli.Context, error) {},
+	}
+	for _, c := range app.Commands {
+		if c.OnUsageError == nil {
+			c.OnUsageError = usageError
+		}
 	}
+	return app
+}
+
+// usageError turns bad flags or arguments into an exit code 2 error.
+func usageError(cCtx *cli.Context, err error, _ bool) error {
+	return cli.Exit(fmt.Sprintf("%v (see %s --help)", err, cCtx.Command.FullName()), 2)
+}
 
-	if err := app.Run(os.Args); err != nil {
-		log.Fatal(err)
+// guiCommand opens the editor window with run.
+func guiCommand(run cli.ActionFunc) *cli.Command {
+	return &cli.Command{
+		Name:   "gui",
+		Usage:  "Open the Covlet editor (the default without a command)",
+		Action: run,
 	}
+}
 
+// resolveHome applies the --home, --profile and --passphrase-file flags before
+// any values file is resolved.
+func resolveHome(cCtx *cli.Context) error {
+	if file := cCtx.String("passphrase-file"); file != "" {
+		p, err := config.ReadPassphraseFile(file)
+		if err != nil {
+			return fmt.Errorf("error reading passphrase: %v", err)
+		}
+		config.SetPassphrase(p)
+	}
+	if err := config.ResolveMainDir(cCtx.String("home")); err != nil {
+		return fmt.Errorf("error resolving main directory: %v", err)
+	}
+	if profile := cCtx.String("profile"); profile != "" {
+		if err := config.UseProfile(profile); err != nil {
+			return err
+		}
+	}
 	return nil
 }
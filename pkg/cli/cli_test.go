@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cliHome creates a main dir with a cover letter template and a resume, and
+// isolates the settings file and environment.
+func cliHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("COVLET_HOME", "")
+	t.Setenv("COVLET_CONFIG", "")
+	files := map[string]string{
+		"templates/base/cover_letter.tpl": "Dear {{ .HiringManager }}, I apply to {{ .CompanyToApplyTo }} as {{ .RoleToApplyTo }}. {{ .Name }}",
+		"templates/partials/sig.tmpl":     "-- {{ .Name }}",
+		"values/resume.yml":               "version: 1\nname: Jane Doe\nemail: jane@example.com\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(home, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return home
+}
+
+// run runs the CLI with args and returns its output and exit code.
+func run(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	var out bytes.Buffer
+	app := newApp(nil)
+	app.Writer, app.ErrWriter = &out, &out
+	err := app.Run(append([]string{"covlet"}, args...))
+	if err != nil {
+		out.WriteString(err.Error())
+	}
+	return out.String(), ExitCode(err)
+}
+
+func TestRender(t *testing.T) {
+	home := cliHome(t)
+	out, code := run(t, "--home", home, "render", "-c", "ACME", "-p", "Engineer", "-m", "Sam")
+	if code != 0 || out != "Dear Sam, I apply to ACME as Engineer. Jane Doe" {
+		t.Fatalf("render = %q (exit %d)", out, code)
+	}
+}
+
+func TestRender_TemplateValuesAndOutput(t *testing.T) {
+	home := cliHome(t)
+	layer := filepath.Join(home, "values", "companies", "acme.yml")
+	if err := os.MkdirAll(filepath.Dir(layer), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(layer, []byte("company_to_apply_to: ACME\ncustom:\n  HiringManager: Sam\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, code := run(t, "--home", home, "render", "--template", "partials/sig", "--values", "companies/acme.yml", "--set", "Name=J. Doe")
+	if code != 0 || out != "-- J. Doe" {
+		t.Fatalf("render = %q (exit %d)", out, code)
+	}
+	out, _ = run(t, "--home", home, "render", "--values", "companies/acme.yml", "-p", "Engineer", "--set", "HiringManager=Ann", "-m", "Bob")
+	if out != "Dear Bob, I apply to ACME as Engineer. Jane Doe" {
+		t.Fatalf("expected the layer and the flags to apply, got %q", out)
+	}
+
+	html := filepath.Join(t.TempDir(), "letter.html")
+	if out, code := run(t, "--home", home, "render", "--out", html, "--title", "Letter"); code != 0 {
+		t.Fatalf("render --out failed with exit %d: %s", code, out)
+	}
+	if b, _ := os.ReadFile(html); !strings.Contains(string(b), "<title>Letter</title>") || !strings.Contains(string(b), "<p>Dear") {
+		t.Fatalf("expected an html page, got:\n%s", b)
+	}
+	pdf := filepath.Join(t.TempDir(), "letter.out")
+	if out, code := run(t, "--home", home, "render", "--out", pdf, "--format", "pdf"); code != 0 {
+		t.Fatalf("render --format pdf failed with exit %d: %s", code, out)
+	}
+	if b, _ := os.ReadFile(pdf); !strings.HasPrefix(string(b), "%PDF") {
+		t.Fatalf("expected a pdf, got %.20q", b)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	home := cliHome(t)
+	jobs := filepath.Join(t.TempDir(), "jobs.csv")
+	if err := os.WriteFile(jobs, []byte("company,role,manager,Experience.x\nACME,Engineer,Sam,\nInitech,SRE,Bob,oops\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outDir := t.TempDir()
+	out, code := run(t, "--home", home, "batch", "--jobs", jobs, "--out-dir", outDir, "--name", "{{ .CompanyToApplyTo }}.md")
+	if code != 1 || !strings.Contains(out, "row 2 (Initech)") || !strings.Contains(out, "rendered 1 of 2 letters; 1 failed") {
+		t.Fatalf("unexpected batch result (exit %d):\n%s", code, out)
+	}
+	b, err := os.ReadFile(filepath.Join(outDir, "ACME.md"))
+	if err != nil || string(b) != "Dear Sam, I apply to ACME as Engineer. Jane Doe" {
+		t.Fatalf("unexpected letter %q, %v", b, err)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	home := cliHome(t)
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"unknown command", []string{"--home", home, "frobnicate"}, 2},
+		{"unknown flag", []string{"--home", home, "render", "--nope"}, 2},
+		{"pdf to stdout", []string{"--home", home, "render", "--format", "pdf"}, 2},
+		{"bad render format", []string{"--home", home, "render", "--format", "docx"}, 2},
+		{"bad override", []string{"--home", home, "render", "--set", "novalue"}, 2},
+		{"missing template", []string{"--home", home, "render", "--template", "nope"}, 1},
+		{"bad export format", []string{"--home", home, "export", "--format", "xml"}, 2},
+		{"missing values file", []string{"--home", home, "--config", filepath.Join(home, "nope.yml"), "render"}, 1},
+		{"validation errors", []string{"--home", home, "validate", filepath.Join(home, "templates/base/cover_letter.tpl")}, 1},
+		{"valid", []string{"--home", home, "validate"}, 0},
+		{"valid layer", []string{"--home", home, "validate", filepath.Join(home, "values", "companies", "acme.yml")}, 0},
+	}
+	layer := filepath.Join(home, "values", "companies", "acme.yml")
+	if err := os.MkdirAll(filepath.Dir(layer), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(layer, []byte("company_to_apply_to: ACME\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if out, code := run(t, tt.args...); code != tt.code {
+				t.Fatalf("exit code %d, want %d; output:\n%s", code, tt.code, out)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	home := cliHome(t)
+	out, code := run(t, "--home", home, "list", "templates")
+	if code != 0 || out != "base/cover_letter.tpl\npartials/sig.tmpl\n" {
+		t.Fatalf("list templates = %q (exit %d)", out, code)
+	}
+	out, _ = run(t, "--home", home, "list")
+	for _, want := range []string{"templates:\n  base/cover_letter.tpl\n", "values:\n", "profiles:\n"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	home := cliHome(t)
+	if _, code := run(t, "--home", home, "new", "template", "--from", "base/cover_letter.tpl", "letters/acme"); code != 0 {
+		t.Fatalf("new template failed with exit %d", code)
+	}
+	b, err := os.ReadFile(filepath.Join(home, "templates", "letters", "acme.tpl"))
+	if err != nil || !strings.HasPrefix(string(b), "Dear") {
+		t.Fatalf("expected a copy of the base template, got %q, %v", b, err)
+	}
+	if out, code := run(t, "--home", home, "new", "template", "letters/acme"); code != 1 {
+		t.Fatalf("expected an existing template to fail, got exit %d: %s", code, out)
+	}
+
+	if out, code := run(t, "--home", home, "new", "values", "--name", "Jane", "--email", "j@example.com"); code != 1 || !strings.Contains(out, "already exists") {
+		t.Fatalf("expected an existing values file to fail, got exit %d: %s", code, out)
+	}
+	if out, code := run(t, "--home", home, "new", "profile", "backend"); code != 0 {
+		t.Fatalf("new profile failed with exit %d: %s", code, out)
+	}
+	b, _ = os.ReadFile(filepath.Join(home, "values", "profiles", "backend.yml"))
+	if !strings.Contains(string(b), "name: Jane Doe") {
+		t.Fatalf("expected the profile to start with the contact details:\n%s", b)
+	}
+
+	// a resume that cannot be read is reported rather than skipped
+	if err := os.WriteFile(filepath.Join(home, "values", "resume.yml"), []byte("name: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, code := run(t, "--home", home, "new", "profile", "broken"); code != 1 {
+		t.Fatalf("expected an unreadable resume to fail, got exit %d: %s", code, out)
+	}
+	if _, err := os.Stat(filepath.Join(home, "values", "profiles", "broken.yml")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected no profile to be created, got %v", err)
+	}
+	// without a resume the profile starts empty
+	if err := os.Remove(filepath.Join(home, "values", "resume.yml")); err != nil {
+		t.Fatal(err)
+	}
+	if out, code := run(t, "--home", home, "new", "profile", "fresh"); code != 0 {
+		t.Fatalf("new profile without a resume failed with exit %d: %s", code, out)
+	}
+}
+
+func TestRender_PartialsAndSiblings(t *testing.T) {
+	home := cliHome(t)
+	letters := filepath.Join(home, "templates", "letters")
+	if err := os.MkdirAll(letters, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"intro.tpl": "Hi {{ .CompanyToApplyTo }}.",
+		"acme.tpl":  `{{ if .Skills }}{{ template "intro" . }} {{ end }}{{ template "sig" . }}`,
+	} {
+		if err := os.WriteFile(filepath.Join(letters, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, code := run(t, "--home", home, "render", "-t", "letters/acme", "-c", "ACME")
+	if code != 0 || out != "-- Jane Doe" {
+		t.Fatalf("render = %q (exit %d)", out, code)
+	}
+	out, code = run(t, "--home", home, "render", "-t", "letters/acme", "-c", "ACME", "--siblings", "--set", "Skills=Go")
+	if code != 0 || out != "Hi ACME. -- Jane Doe" {
+		t.Fatalf("render --siblings = %q (exit %d)", out, code)
+	}
+}
+
+func TestRender_Layouts(t *testing.T) {
+	home := cliHome(t)
+	for name, content := range map[string]string{
+		"layouts/base.tpl": `{{ block "body" . }}Hello{{ end }} {{ block "closing" . }}{{ template "sig" . }}{{ end }}`,
+		"letters/acme.tpl": "{{/* extends \"layouts/base\" */}}\n{{ define \"body\" }}Hi {{ .CompanyToApplyTo }}.{{ end }}\n",
+		"letters/loop.tpl": `{{/* extends "letters/loop" */}}`,
+	} {
+		path := filepath.Join(home, "templates", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, code := run(t, "--home", home, "render", "-t", "letters/acme", "-c", "ACME")
+	if code != 0 || out != "Hi ACME. -- Jane Doe" {
+		t.Fatalf("render = %q (exit %d)", out, code)
+	}
+	out, code = run(t, "--home", home, "render", "-t", "letters/acme", "--blocks")
+	for _, want := range []string{"extends: layouts/base.tpl\n", "body     letters/acme.tpl\n", "closing  layouts/base.tpl\n", "sig      partials/sig.tmpl\n"} {
+		if code != 0 || !strings.Contains(out, want) {
+			t.Fatalf("expected %q in --blocks output (exit %d):\n%s", want, code, out)
+		}
+	}
+	if out, code := run(t, "--home", home, "render", "-t", "letters/loop"); code != 1 || !strings.Contains(out, "layout cycle") {
+		t.Fatalf("expected a layout cycle error, got exit %d: %s", code, out)
+	}
+}
+
+func TestRender_FrontMatter(t *testing.T) {
+	home := cliHome(t)
+	letter := filepath.Join(home, "templates", "letters", "acme.tpl")
+	if err := os.MkdirAll(filepath.Dir(letter), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\ntitle: ACME letter\noutput: \"{{ .CompanyToApplyTo }}.html\"\npdf_title: \"For {{ .CompanyToApplyTo }}\"\nrequired: [CompanyToApplyTo]\ndefaults: {HiringManager: Hiring Manager}\n---\nDear {{ .HiringManager }}, {{ .Name }}"
+	if err := os.WriteFile(letter, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, code := run(t, "--home", home, "render", "-t", "letters/acme"); code != 1 || !strings.Contains(out, "missing required variables: CompanyToApplyTo") {
+		t.Fatalf("expected a missing variable error, got exit %d: %s", code, out)
+	}
+	out, code := run(t, "--home", home, "render", "-t", "letters/acme", "-c", "ACME")
+	if code != 0 || out != "Dear Hiring Manager, Jane Doe" {
+		t.Fatalf("render = %q (exit %d)", out, code)
+	}
+	dir := t.TempDir()
+	if out, code := run(t, "--home", home, "render", "-t", "letters/acme", "-c", "ACME", "--out", dir); code != 0 {
+		t.Fatalf("render --out DIR failed with exit %d: %s", code, out)
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "ACME.html")); err != nil || !strings.Contains(string(b), "<title>For ACME</title>") {
+		t.Fatalf("expected ACME.html with the template's title, got %q, %v", b, err)
+	}
+	if out, code := run(t, "--home", home, "render", "--out", dir); code != 2 {
+		t.Fatalf("expected a template without an output name to fail, got exit %d: %s", code, out)
+	}
+
+	jobs := filepath.Join(t.TempDir(), "jobs.csv")
+	if err := os.WriteFile(jobs, []byte("company,manager\nInitech,Bob\n,Ann\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outDir := t.TempDir()
+	out, code = run(t, "--home", home, "batch", "-t", "letters/acme", "--jobs", jobs, "--out-dir", outDir)
+	if code != 1 || !strings.Contains(out, "row 2: missing required variables: CompanyToApplyTo") {
+		t.Fatalf("unexpected batch result (exit %d):\n%s", code, out)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "Initech.html")); err != nil {
+		t.Fatalf("expected the template's output name: %v", err)
+	}
+}
+
+func TestRender_Strict(t *testing.T) {
+	home := cliHome(t)
+	letter := filepath.Join(home, "templates", "letters", "acme.tpl")
+	if err := os.MkdirAll(filepath.Dir(letter), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "Dear {{ .HiringManager }},\n{{ .Name }} for {{ .CompanyToApplyTo }}{{ .Nope }}"
+	if err := os.WriteFile(letter, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, code := run(t, "--home", home, "render", "-t", "letters/acme"); code != 0 {
+		t.Fatalf("render without --strict failed with exit %d: %s", code, out)
+	}
+	out, code := run(t, "--home", home, "render", "-t", "letters/acme", "--strict")
+	if code != 1 {
+		t.Fatalf("expected --strict to fail, got exit %d: %s", code, out)
+	}
+	for _, want := range []string{
+		"3 unresolved references",
+		"letters/acme.tpl:1:9: .HiringManager is not set",
+		"letters/acme.tpl:2:20: .CompanyToApplyTo is empty",
+		"letters/acme.tpl:2:43: .Nope is not set",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	out, code = run(t, "--home", home, "render", "-t", "letters/acme", "--strict", "-m", "Sam", "-c", "ACME", "--set", "Nope=x")
+	if code != 0 || out != "Dear Sam,\nJane Doe for ACMEx" {
+		t.Fatalf("render --strict = %q (exit %d)", out, code)
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"covlet/pkg/config"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// encryptCommand encrypts the values file in place with the --passphrase-file passphrase.
+func encryptCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "encrypt",
+		Usage: "Encrypt the resume values file with the passphrase from --passphrase-file",
+		Action: func(cCtx *cli.Context) error {
+			return setEncrypted(cCtx, true)
+		},
+	}
+}
+
+// decryptCommand writes the values file back as plain text.
+func decryptCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "decrypt",
+		Usage: "Remove encryption from the resume values file",
+		Action: func(cCtx *cli.Context) error {
+			return setEncrypted(cCtx, false)
+		},
+	}
+}
+
+func setEncrypted(cCtx *cli.Context, on bool) error {
+	if err := resolveHome(cCtx); err != nil {
+		return err
+	}
+	if !config.HasPassphrase() {
+		return fmt.Errorf("a passphrase is required; use --passphrase-file")
+	}
+	path, err := config.ResolveConfigFile(cCtx.String("config"))
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return fmt.Errorf("error loading config: %v", err)
+	}
+	if cfg.Encrypted() == on {
+		fmt.Fprintf(cCtx.App.Writer, "%s: nothing to do\n", path)
+		return nil
+	}
+	if err := cfg.SetEncrypted(on); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("error saving %s: %v", path, err)
+	}
+	state := "encrypted"
+	if !on {
+		state = "decrypted"
+	}
+	fmt.Fprintf(cCtx.App.Writer, "%s: %s\n", path, state)
+	return nil
+}
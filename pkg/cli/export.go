@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"covlet/pkg/config"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
+
+// exportFormats maps the --format values of the export command to encoders.
+var exportFormats = map[string]func(config.Resume) ([]byte, error){
+	"jsonresume": config.ExportJSONResume,
+	"json":       config.ExportJSON,
+}
+
+// exportCommand writes the loaded resume as JSON Resume or plain JSON.
+func exportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export the resume values file as JSON Resume or plain JSON",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "jsonresume",
+				Usage: "Output format: jsonresume or json",
+			},
+			&cli.StringFlag{
+				Name:  "out",
+				Usage: "File to write (default: standard output)",
+			},
+		},
+		Action: func(cCtx *cli.Context) error {
+			encode, ok := exportFormats[cCtx.String("format")]
+			if !ok {
+				return cli.Exit(fmt.Sprintf("unknown format %q (use jsonresume or json)", cCtx.String("format")), 2)
+			}
+			if err := resolveHome(cCtx); err != nil {
+				return err
+			}
+			configPath, err := config.ResolveConfigFile(cCtx.String("config"))
+			if err != nil {
+				return err
+			}
+			configFile, err := config.LoadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+			b, err := encode(configFile.Resume)
+			if err != nil {
+				return fmt.Errorf("error exporting resume: %v", err)
+			}
+			b = append(b, '\n')
+
+			if out := cCtx.String("out"); out != "" {
+				return os.WriteFile(out, b, 0o644)
+			}
+			_, err = cCtx.App.Writer.Write(b)
+			return err
+		},
+	}
+}
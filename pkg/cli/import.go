@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bufio"
+	"covlet/pkg/config"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// importCommand converts a jsonresume.org resume.json, or merges a LinkedIn
+// data export zip, into a values file.
+func importCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import a JSON Resume (jsonresume.org) file or a LinkedIn data export zip into the resume values file",
+		ArgsUsage: "resume.json|linkedin-export.zip",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "out",
+				Usage: "Values file to write (default: the resume values file of the selected profile)",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Replace the resume data in an existing values file (JSON Resume)",
+			},
+			&cli.StringFlag{
+				Name:  "on-conflict",
+				Value: "ask",
+				Usage: "When a LinkedIn value differs from the existing one: ask, keep or theirs",
+			},
+		},
+		Action: func(cCtx *cli.Context) error {
+			if cCtx.NArg() != 1 {
+				return cli.Exit("usage: import [--out file] [--force] [--on-conflict ask|keep|theirs] resume.json|export.zip", 2)
+			}
+			src := cCtx.Args().First()
+			linkedIn := strings.EqualFold(filepath.Ext(src), ".zip")
+			resolve, err := conflictResolver(cCtx)
+			if err != nil {
+				return err
+			}
+			if err := resolveHome(cCtx); err != nil {
+				return err
+			}
+
+			var imported config.Resume
+			var unmapped []string
+			if linkedIn {
+				imported, unmapped, err = config.ImportLinkedInFile(src)
+			} else {
+				imported, unmapped, err = config.ImportJSONResumeFile(src)
+			}
+			if err != nil {
+				return err
+			}
+
+			out := cCtx.String("out")
+			if out == "" {
+				// a missing values file is fine; it is created below
+				out, _ = config.ResolveConfigFile(cCtx.String("config"))
+			}
+			cfg, err := config.LoadConfig(out)
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				cfg = &config.Config{Resume: imported}
+			case err != nil:
+				return fmt.Errorf("error loading config: %v", err)
+			case linkedIn:
+				cfg.Resume = config.MergeResumes(cfg.Resume, imported, resolve)
+			case !cCtx.Bool("force"):
+				return cli.Exit(fmt.Sprintf("%s already exists; use --force to replace its resume data", out), 1)
+			default:
+				cfg.Resume = config.ImportInto(cfg.Resume, imported)
+			}
+			if err := cfg.SaveConfig(out); err != nil {
+				return fmt.Errorf("error saving config: %v", err)
+			}
+
+			for _, u := range unmapped {
+				fmt.Fprintf(cCtx.App.ErrWriter, "not imported: %s\n", u)
+			}
+			fmt.Fprintf(cCtx.App.Writer, "imported %s into %s\n", src, out)
+			return nil
+		},
+	}
+}
+
+// conflictResolver returns the merge decision for the --on-conflict flag.
+// "ask" prompts on the app's reader; end of input keeps the current value.
+func conflictResolver(cCtx *cli.Context) (func(config.Conflict) bool, error) {
+	switch mode := cCtx.String("on-conflict"); mode {
+	case "keep":
+		return nil, nil
+	case "theirs":
+		return func(config.Conflict) bool { return true }, nil
+	case "ask":
+		in := bufio.NewScanner(cCtx.App.Reader)
+		return func(c config.Conflict) bool {
+			fmt.Fprintf(cCtx.App.Writer, "%s\n  current:  %s\n  imported: %s\nUse imported value? [y/N] ", c.Path, c.Current, c.Incoming)
+			if !in.Scan() {
+				fmt.Fprintln(cCtx.App.Writer)
+				return false
+			}
+			answer := strings.ToLower(strings.TrimSpace(in.Text()))
+			return answer == "y" || answer == "yes"
+		}, nil
+	default:
+		return nil, cli.Exit(fmt.Sprintf("unknown --on-conflict value %q (use ask, keep or theirs)", mode), 2)
+	}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"covlet/pkg/config"
+	"covlet/pkg/internal"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// listCommand prints templates, values files and profiles, one per line.
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "list",
+		Usage:     "List templates, values files and profiles",
+		ArgsUsage: "[templates|values|profiles]",
+		Action: func(cCtx *cli.Context) error {
+			sections := []string{"templates", "values", "profiles"}
+			if cCtx.NArg() > 1 {
+				return cli.Exit("usage: list [templates|values|profiles]", 2)
+			}
+			if what := cCtx.Args().First(); what != "" {
+				found := false
+				for _, s := range sections {
+					found = found || s == what
+				}
+				if !found {
+					return cli.Exit(fmt.Sprintf("unknown list %q (use templates, values or profiles)", what), 2)
+				}
+				sections = []string{what}
+			}
+			if err := resolveHome(cCtx); err != nil {
+				return err
+			}
+
+			out := cCtx.App.Writer
+			for i, section := range sections {
+				var items []string
+				var err error
+				switch section {
+				case "templates":
+					items, err = listTemplates()
+				case "values":
+					items, err = config.ListValuesFiles()
+				case "profiles":
+					items, err = config.ListProfiles()
+					for j, name := range items {
+						if name == config.ActiveProfile() {
+							items[j] += " (active)"
+						}
+					}
+				}
+				if err != nil {
+					return fmt.Errorf("error listing %s: %w", section, err)
+				}
+				// a single section is printed bare so it can be piped
+				if len(sections) > 1 {
+					if i > 0 {
+						fmt.Fprintln(out)
+					}
+					fmt.Fprintf(out, "%s:\n", section)
+				}
+				for _, item := range items {
+					if len(sections) > 1 {
+						item = "  " + item
+					}
+					fmt.Fprintln(out, item)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// listTemplates returns the template files under TemplatesDir as slash
+// separated relative paths.
+func listTemplates() ([]string, error) {
+	root := config.TemplatesDir()
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == root {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != root {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !internal.IsTemplateFile(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		out = append(out, filepath.ToSlash(rel))
+		return nil
+	})
+	return out, err
+}
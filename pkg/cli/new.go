@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"covlet/pkg/config"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// newCommand creates templates, the resume values file and profiles.
+func newCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "new",
+		Usage: "Create a template, the resume values file or a profile",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "template",
+				Usage:     "Create a template under the templates directory",
+				ArgsUsage: "name",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "from",
+						Usage: "Template to copy, relative to the templates directory (default: an empty file)",
+					},
+				},
+				Action: newTemplate,
+			},
+			{
+				Name:  "values",
+				Usage: "Create the resume values file without the setup wizard",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "name", Usage: "Your full name", Required: true},
+					&cli.StringFlag{Name: "email", Usage: "Your email address", Required: true},
+					&cli.StringFlag{Name: "phone", Usage: "Your phone number"},
+				},
+				Action: newValues,
+			},
+			{
+				Name:      "profile",
+				Usage:     "Create a profile starting from the contact details of the current resume",
+				ArgsUsage: "name",
+				Action:    newProfile,
+			},
+		},
+	}
+}
+
+func newTemplate(cCtx *cli.Context) error {
+	if cCtx.NArg() != 1 {
+		return cli.Exit("usage: new template [--from template] name", 2)
+	}
+	if err := resolveHome(cCtx); err != nil {
+		return err
+	}
+	name := filepath.FromSlash(cCtx.Args().First())
+	if filepath.Ext(name) == "" {
+		name += ".tpl"
+	}
+	root := config.TemplatesDir()
+	path := filepath.Join(root, name)
+	if rel, err := filepath.Rel(root, path); err != nil || strings.HasPrefix(rel, "..") {
+		return cli.Exit(fmt.Sprintf("template %q must be inside the templates directory", cCtx.Args().First()), 2)
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("template already exists: %s: %w", path, fs.ErrExist)
+	}
+
+	var content []byte
+	if from := cCtx.String("from"); from != "" {
+		b, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(from)))
+		if err != nil {
+			return fmt.Errorf("error reading template %s: %w", from, err)
+		}
+		content = b
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintln(cCtx.App.Writer, path)
+	return nil
+}
+
+func newValues(cCtx *cli.Context) error {
+	if err := resolveHome(cCtx); err != nil {
+		return err
+	}
+	cfg := &config.Config{Resume: config.Resume{
+		Name:  cCtx.String("name"),
+		Email: cCtx.String("email"),
+		Phone: cCtx.String("phone"),
+	}}
+	if err := cfg.CreateConfig(); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return cli.Exit(err.Error(), 1)
+		}
+		return fmt.Errorf("error creating values file: %w", err)
+	}
+	fmt.Fprintln(cCtx.App.Writer, cfg.Path())
+	return nil
+}
+
+func newProfile(cCtx *cli.Context) error {
+	if cCtx.NArg() != 1 {
+		return cli.Exit("usage: new profile name", 2)
+	}
+	if err := resolveHome(cCtx); err != nil {
+		return err
+	}
+	// a new persona starts with the current contact details, as in the app
+	var r config.Resume
+	path, err := config.ResolveConfigFile(cCtx.String("config"))
+	if err == nil {
+		var cur *config.Config
+		if cur, err = config.LoadConfig(path); err == nil {
+			r = config.ContactOnly(cur.Resume)
+		}
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	cfg, err := config.CreateProfile(cCtx.Args().First(), r)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cCtx.App.Writer, cfg.Path())
+	return nil
+}
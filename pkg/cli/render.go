@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"bytes"
+	"covlet/pkg/config"
+	"covlet/pkg/internal"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// defaultTemplate is rendered when --template is not given.
+const defaultTemplate = "base/cover_letter.tpl"
+
+// renderCommand renders a template with the resume values, optionally layered
+// and overridden, to standard output or a file.
+func renderCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "render",
+		Usage: "Render a template with the resume values",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "template",
+				Aliases: []string{"t"},
+				Value:   defaultTemplate,
+				Usage:   "Template name relative to the templates directory (the extension may be left off), or a path",
+			},
+			&cli.StringSliceFlag{
+				Name:  "values",
+				Usage: "Values file layered over the resume, relative to the values directory or a path; repeatable, later files win",
+			},
+			&cli.StringSliceFlag{
+				Name:  "set",
+				Usage: "Override a value, e.g. --set HiringManager=Sam or --set Experience.0.Company=ACME; repeatable",
+			},
+			&cli.StringFlag{
+				Name:    "company",
+				Aliases: []string{"c"},
+				Usage:   "Company to apply to",
+			},
+			&cli.StringFlag{
+				Name:    "manager",
+				Aliases: []string{"m"},
+				Usage:   "Hiring manager's name, available to templates as {{ .HiringManager }}",
+			},
+			&cli.StringFlag{
+				Name:    "position",
+				Aliases: []string{"p"},
+				Usage:   "Position to apply for",
+			},
+			&cli.StringFlag{
+				Name:    "out",
+				Aliases: []string{"o"},
+				Usage:   "File to write, or a directory to write the template's output file into (default: standard output)",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: txt, md, html or pdf (default: from the --out extension, else txt)",
+			},
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "Document title for pdf and html output (default: the template's title)",
+			},
+			siblingsFlag(),
+			&cli.BoolFlag{
+				Name:  "blocks",
+				Usage: "Instead of rendering, list the layouts the template extends and the file each block comes from",
+			},
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: "Fail on missing values and on empty values the template prints, listing every one with its line",
+			},
+		},
+		Action: func(cCtx *cli.Context) error {
+			out := cCtx.String("out")
+			format := internal.FormatOf(out)
+			if f := cCtx.String("format"); f != "" {
+				var err error
+				if format, err = internal.ParseFormat(f); err != nil {
+					return cli.Exit(err.Error(), 2)
+				}
+			}
+			if format == internal.FormatPDF && out == "" {
+				return cli.Exit("pdf output needs --out", 2)
+			}
+			overrides, err := renderOverrides(cCtx)
+			if err != nil {
+				return cli.Exit(err.Error(), 2)
+			}
+			if err := resolveHome(cCtx); err != nil {
+				return err
+			}
+
+			letter, err := parseTemplate(cCtx)
+			if err != nil {
+				return err
+			}
+			if cCtx.Bool("blocks") {
+				printBlocks(cCtx.App.Writer, letter)
+				return nil
+			}
+
+			stack, err := loadStack(cCtx.String("config"), cCtx.StringSlice("values"))
+			if err != nil {
+				return err
+			}
+			stack, err = stack.WithOverrides(overrides)
+			if err != nil {
+				return fmt.Errorf("error applying overrides: %w", err)
+			}
+			resume, err := letter.Meta.Apply(stack.Resume)
+			if err != nil {
+				return err
+			}
+			data := resume.Data()
+			var text []byte
+			if cCtx.Bool("strict") {
+				text, err = letter.RenderStrict(data)
+			} else {
+				text, err = internal.RenderEditor(letter.Template, data)
+			}
+			if err != nil {
+				return fmt.Errorf("error executing template: %w", err)
+			}
+
+			title := cCtx.String("title")
+			if title == "" {
+				if title, err = letter.Meta.DocumentTitle(data); err != nil {
+					return err
+				}
+			}
+			if fi, err := os.Stat(out); err == nil && fi.IsDir() {
+				name, err := letter.Meta.OutputName(data)
+				if err != nil {
+					return err
+				}
+				if name == "" {
+					return cli.Exit(fmt.Sprintf("%s is a directory and the template names no output file", out), 2)
+				}
+				out = filepath.Join(out, name)
+				if cCtx.String("format") == "" {
+					format = internal.FormatOf(out)
+				}
+			}
+			if out == "" {
+				return internal.WriteOutput(cCtx.App.Writer, text, format, title)
+			}
+			var buf bytes.Buffer
+			if err := internal.WriteOutput(&buf, text, format, title); err != nil {
+				return err
+			}
+			return os.WriteFile(out, buf.Bytes(), 0o644)
+		},
+	}
+}
+
+// renderOverrides collects --set Key=Value and the --company, --position and
+// --manager shortcuts, which win over --set.
+func renderOverrides(cCtx *cli.Context) (map[string]string, error) {
+	overrides := map[string]string{}
+	for _, kv := range cCtx.StringSlice("set") {
+		k, v, ok := strings.Cut(kv, "=")
+		if k = strings.TrimSpace(k); !ok || k == "" {
+			return nil, fmt.Errorf("invalid --set %q (use Key=Value)", kv)
+		}
+		overrides[k] = v
+	}
+	for flag, path := range map[string]string{
+		"company":  "CompanyToApplyTo",
+		"position": "RoleToApplyTo",
+		"manager":  "HiringManager",
+	} {
+		if v := cCtx.String(flag); v != "" {
+			overrides[path] = v
+		}
+	}
+	return overrides, nil
+}
+
+// loadStack merges the resume values file with the given values layers.
+func loadStack(configFlag string, values []string) (*config.Stack, error) {
+	configPath, err := config.ResolveConfigFile(configFlag)
+	if err != nil {
+		return nil, err
+	}
+	base, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+	layers := []config.Layer{{Name: config.LayerName(configPath), Config: base}}
+	for _, v := range values {
+		path := v
+		if _, err := os.Stat(path); err != nil && !filepath.IsAbs(path) {
+			path = filepath.Join(config.ValuesDir(), filepath.FromSlash(v))
+		}
+		l, err := config.LoadLayer(path)
+		if err != nil {
+			return nil, fmt.Errorf("error loading values %s: %w", v, err)
+		}
+		layers = append(layers, l)
+	}
+	return config.MergeLayers(layers...)
+}
+
+// siblingsFlag makes the templates next to the rendered one available to it,
+// in addition to the partials.
+func siblingsFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "siblings",
+		Usage: "Also load the templates in the same directory, by name without extension, e.g. {{ template \"intro\" . }}",
+	}
+}
+
+// parseTemplate resolves --template and parses it with the partials and
+// layouts (and, with --siblings, its sibling templates).
+func parseTemplate(cCtx *cli.Context) (*internal.Letter, error) {
+	path, err := resolveTemplate(cCtx.String("template"))
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template file: %w", err)
+	}
+	r := &internal.Renderer{TemplatesDir: config.TemplatesDir(), Siblings: cCtx.Bool("siblings")}
+	l, err := r.Load(path, string(b))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %w", err)
+	}
+	return l, nil
+}
+
+// printBlocks lists the layouts a letter extends and the file each of its
+// blocks comes from.
+func printBlocks(w io.Writer, l *internal.Letter) {
+	if len(l.Layouts) > 0 {
+		fmt.Fprintf(w, "extends: %s\n", strings.Join(l.Layouts, " -> "))
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, name := range l.BlockNames() {
+		fmt.Fprintf(tw, "%s\t%s\n", name, l.Blocks[name])
+	}
+	tw.Flush()
+}
+
+// resolveTemplate finds a template given as a path, or as a name relative to
+// TemplatesDir with or without its extension.
+func resolveTemplate(name string) (string, error) {
+	if fi, err := os.Stat(name); err == nil && !fi.IsDir() {
+		return name, nil
+	}
+	candidates := []string{filepath.Join(config.TemplatesDir(), filepath.FromSlash(name))}
+	if filepath.Ext(name) == "" {
+		for _, ext := range internal.TemplateExts {
+			candidates = append(candidates, candidates[0]+ext)
+		}
+	}
+	for _, c := range candidates {
+		if fi, err := os.Stat(c); err == nil && !fi.IsDir() {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("template %q not found in %s", name, config.TemplatesDir())
+}
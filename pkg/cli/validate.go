@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"covlet/pkg/config"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// validateCommand checks values files and exits non-zero when any has errors.
+func validateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "validate",
+		Usage:     "Check resume values files for problems",
+		ArgsUsage: "[values files...]",
+		Action: func(cCtx *cli.Context) error {
+			if err := resolveHome(cCtx); err != nil {
+				return err
+			}
+			files := cCtx.Args().Slice()
+			if len(files) == 0 {
+				path, err := config.ResolveConfigFile(cCtx.String("config"))
+				if err != nil {
+					return err
+				}
+				files = []string{path}
+			}
+
+			errs, warns := 0, 0
+			for _, f := range files {
+				// layers under values/ are partial: they need no name or email
+				validate := config.ValidateFile
+				if config.IsLayerFile(f) {
+					validate = config.ValidateLayerFile
+				}
+				diags, err := validate(f)
+				if err != nil {
+					return fmt.Errorf("error validating %s: %v", f, err)
+				}
+				for _, d := range diags {
+					if d.Severity == config.SeverityError {
+						errs++
+					} else {
+						warns++
+					}
+					fmt.Fprintf(cCtx.App.Writer, "%s:%s\n", f, d)
+				}
+			}
+			if errs > 0 {
+				return cli.Exit(fmt.Sprintf("%d error(s), %d warning(s)", errs, warns), 1)
+			}
+			fmt.Fprintf(cCtx.App.Writer, "ok: %d file(s), %d warning(s)\n", len(files), warns)
+			return nil
+		},
+	}
+}
@@ -3,14 +3,18 @@ package config
 import (
     "errors"
     "fmt"
+    "io/fs"
     "os"
     "path/filepath"
+    "reflect"
 
     "gopkg.in/yaml.v3"
 )
 
 // Resume holds all the information from the YAML file.
 type Resume struct {
+	// Version is the schema version of the values file; see SchemaVersion.
+	Version          int          `yaml:"version,omitempty"`
 	Name             string       `yaml:"name"`
 	Email            string       `yaml:"email"`
 	Phone            string       `yaml:"phone"`
@@ -23,14 +27,42 @@ type Resume struct {
 	Projects         []Project    `yaml:"projects"`
 	CompanyToApplyTo string       `yaml:"company_to_apply_to"`
 	RoleToApplyTo    string       `yaml:"role_to_apply_to"`
+	// Custom holds free-form template variables such as HiringManager or PostingURL.
+	Custom map[string]any `yaml:"custom,omitempty"`
+	// Extra collects any other top-level keys in the values file; they are
+	// exposed to templates like Custom but with lower precedence.
+	Extra map[string]any `yaml:",inline"`
+}
+
+// Data returns the values exposed to templates. Typed fields are keyed by
+// their Go names (.Name, .Experience, ...). Free-form variables are merged in
+// with this precedence, highest first: typed fields, the custom section, then
+// extra top-level keys. Sidebar and --set overrides are applied to the Resume
+// before calling Data, so they win over all of these.
+func (r Resume) Data() map[string]any {
+	data := make(map[string]any, len(r.Extra)+len(r.Custom)+16)
+	for k, v := range r.Extra {
+		data[k] = v
+	}
+	for k, v := range r.Custom {
+		data[k] = v
+	}
+	rv := reflect.ValueOf(r)
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		if sf := rt.Field(i); sf.IsExported() && sf.Name != "Extra" && sf.Name != "Version" {
+			data[sf.Name] = rv.Field(i).Interface()
+		}
+	}
+	return data
 }
 
 // Education represents a single educational entry.
 type Education struct {
 	Institution string `yaml:"institution"`
 	Degree      string `yaml:"degree"`
-	StartDate   string `yaml:"start_date"`
-	EndDate     string `yaml:"end_date"`
+	StartDate   Date   `yaml:"start_date"`
+	EndDate     Date   `yaml:"end_date"`
 	GPA         string `yaml:"gpa"`
 }
 
@@ -38,9 +70,11 @@ type Education struct {
 type Experience struct {
 	Company          string   `yaml:"company"`
 	Position         string   `yaml:"position"`
-	StartDate        string   `yaml:"start_date"`
-	EndDate          string   `yaml:"end_date"`
+	StartDate        Date     `yaml:"start_date"`
+	EndDate          Date     `yaml:"end_date"`
 	Responsibilities []string `yaml:"responsibilities"`
+	// Tags label the entry for the filterByTag template helper, e.g. backend.
+	Tags []string `yaml:"tags,omitempty"`
 }
 
 // Project represents a single project entry.
@@ -48,38 +82,62 @@ type Project struct {
 	Name        string `yaml:"name"`
 	Description string `yaml:"description"`
 	URL         string `yaml:"url"`
+	// Tags label the entry for the filterByTag template helper.
+	Tags []string `yaml:"tags,omitempty"`
 }
 
 // Config handles loading of the resume configuration
 type Config struct {
 	Resume Resume
+
+	// path is the file the config was loaded from or last saved to
+	path string
+	// doc is the YAML document the config was loaded from, if any, with its
+	// references unresolved
+	doc *yaml.Node
+	// refs maps the references in doc to their resolved values
+	refs refs
+	// encrypted saves the values file encrypted; see crypt.go
+	encrypted bool
 }
 
 // application main directory (not persisted to YAML by default)
 var mainDir string
 
-// InitMainDir initializes the app main directory using environment or current working dir.
+// InitMainDir initializes the app main directory; see ResolveMainDir.
 // Environment variable: COVLET_HOME
 func InitMainDir() {
-    if md := os.Getenv("COVLET_HOME"); md != "" {
-        if abs, err := filepath.Abs(md); err == nil {
-            if info, err2 := os.Stat(abs); err2 == nil && info.IsDir() {
-                mainDir = abs
-                return
-            }
-        }
-    }
-    // Default to a directory in the user's home: ~/.local/share/covlet
-    if home, err := os.UserHomeDir(); err == nil {
-        def := filepath.Join(home, ".local", "share", "covlet")
-        _ = os.MkdirAll(def, 0o755)
-        mainDir = def
-        return
-    }
-    // Fallback to current working directory
-    if cwd, err := os.Getwd(); err == nil {
-        mainDir = cwd
-    }
+	_ = ResolveMainDir("")
+}
+
+// ResolveMainDir picks the app main directory, in order of precedence:
+// explicit (e.g. a --home flag), the COVLET_HOME environment variable, the
+// main dir remembered in the settings file, ~/.local/share/covlet, and finally
+// the current working directory. Only an unusable explicit dir is an error.
+func ResolveMainDir(explicit string) error {
+	if explicit != "" {
+		return SetMainDir(explicit)
+	}
+	if md := os.Getenv(HomeEnv); md != "" && SetMainDir(md) == nil {
+		return nil
+	}
+	if st, err := LoadSettings(); err == nil && st.MainDir != "" && SetMainDir(st.MainDir) == nil {
+		return nil
+	}
+	// Default to a directory in the user's home: ~/.local/share/covlet
+	if home, err := os.UserHomeDir(); err == nil {
+		def := filepath.Join(home, ".local", "share", "covlet")
+		_ = os.MkdirAll(def, 0o755)
+		mainDir = def
+		return nil
+	}
+	// Fallback to current working directory
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	mainDir = cwd
+	return nil
 }
 
 // SetMainDir sets the application's main directory; must exist and be a directory.
@@ -168,30 +226,169 @@ func EnsureDownloadsCovletDir() (string, error) {
     return dir, nil
 }
 
+// DefaultValuesFile is the name of the resume values file kept in ValuesDir.
+const DefaultValuesFile = "resume.yml"
+
+// ResumePath returns the path of the default resume values file.
+func ResumePath() string {
+	return filepath.Join(ValuesDir(), DefaultValuesFile)
+}
+
+// resumeFiles are the names the default resume values file may have in
+// ValuesDir, in order of preference.
+var resumeFiles = []string{DefaultValuesFile, "resume.yaml", "resume.json", "resume.toml"}
+
+// isResumeFile reports whether rel, relative to ValuesDir, names the default resume.
+func isResumeFile(rel string) bool {
+	for _, f := range resumeFiles {
+		if rel == f {
+			return true
+		}
+	}
+	return false
+}
+
 // TODO: figure out why URLs don't work for the template generator
 
-// LoadConfig reads and parses the YAML configuration file
+// LoadConfig reads and parses a resume values file. The format is detected
+// from the extension (see FormatOf): YAML, JSON or TOML. Environment and file
+// references in the file are resolved; see resolveReferences. A file at an
+// older schema version is migrated, and rewritten if that changed any values;
+// see migrateDocument. An encrypted file is decrypted with the passphrase
+// from SetPassphrase and saved encrypted again.
 func LoadConfig(filename string) (*Config, error) {
-	yamlFile, err := os.ReadFile(filename)
+	yamlFile, encrypted, err := readValuesFile(filename)
 
-	// if file does not exist, run through a setup and create file
-	// ask for user input to create the directory and file
+	// a missing file is reported as fs.ErrNotExist; callers run the setup
+	// wizard (pkg/setup) to create it
 	if err != nil {
 		return nil, err
 	}
 
-	var resume Resume
-	if err = yaml.Unmarshal(yamlFile, &resume); err != nil {
+	// keep the parsed document around so SaveConfig can preserve comments and key order
+	doc, err := parseDocument(yamlFile, FormatOf(filename))
+	if err != nil {
 		return nil, err
 	}
 
-	return &Config{Resume: resume}, nil
+	// upgrade files written for an older schema, keeping a backup
+	from, changes, err := migrateDocument(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	resolved, refs, err := resolveReferences(doc, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var resume Resume
+	if resolved.Kind != 0 {
+		if err = resolved.Decode(&resume); err != nil {
+			return nil, err
+		}
+	}
+	if len(changes) > 0 {
+		if err := migrateFile(filename, doc, from, changes, encrypted); err != nil {
+			return nil, fmt.Errorf("migrate %s: %w", filename, err)
+		}
+	}
+
+	return &Config{Resume: resume, path: filename, doc: doc, refs: refs, encrypted: encrypted}, nil
+}
+
+// Encrypted reports whether the config is saved encrypted.
+func (c *Config) Encrypted() bool {
+	return c.encrypted
+}
+
+// SetEncrypted turns encryption of the saved values file on or off; it takes
+// effect on the next save. Encrypting needs a passphrase (see SetPassphrase).
+func (c *Config) SetEncrypted(on bool) error {
+	if on && !HasPassphrase() {
+		return ErrPassphraseRequired
+	}
+	c.encrypted = on
+	return nil
 }
 
+// Path returns the file the config was loaded from or last saved to.
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Save writes the config back to the file it was loaded from.
+func (c *Config) Save() error {
+	if c.path == "" {
+		return errors.New("config has no file path; use SaveConfig")
+	}
+	return c.SaveConfig(c.path)
+}
+
+// SaveConfig writes the resume to filename in the format of its extension
+// (see FormatOf). When the config was loaded from a file, or filename already
+// exists, its key order and, for YAML, its comments are kept and only the
+// values are replaced. Empty values are only written for keys the file
+// already has. References whose value is unchanged stay in place, so
+// secrets pulled from the environment or other files are not written out.
+// The file is written atomically, and encrypted when the config is (see
+// SetEncrypted).
 func (c *Config) SaveConfig(filename string) error {
+	if c.Resume.Version == 0 {
+		c.Resume.Version = SchemaVersion
+	}
+	var fresh yaml.Node
+	if err := fresh.Encode(c.Resume); err != nil {
+		return fmt.Errorf("encode resume: %w", err)
+	}
+
+	doc, keep := c.doc, c.refs
+	if doc == nil || doc.Kind == 0 {
+		doc, keep = nil, nil
+		if b, _, err := readValuesFile(filename); err == nil {
+			if existing, err := parseDocument(b, FormatOf(filename)); err == nil && existing.Kind == yaml.DocumentNode {
+				doc = existing
+				_, keep, _ = resolveReferences(existing, filename)
+			}
+		}
+	}
+	if doc != nil && len(doc.Content) > 0 {
+		mergeNode(doc.Content[0], &fresh, keep)
+	} else {
+		dropEmpty(&fresh)
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&fresh}}
+	}
+
+	b, err := encodeDocument(doc, FormatOf(filename))
+	if err != nil {
+		return err
+	}
+	if c.encrypted {
+		if b, err = encrypt(b); err != nil {
+			return err
+		}
+	}
+	if err := writeFileAtomic(filename, b, 0o644); err != nil {
+		return err
+	}
+	c.path = filename
+	c.doc = doc
+	c.refs = keep
 	return nil
 }
 
+// CreateConfig scaffolds a new resume values file at ResumePath from the
+// current Resume. An existing file is never overwritten.
 func (c *Config) CreateConfig() error {
-	return nil
+	if _, err := EnsureValuesDir(); err != nil {
+		return err
+	}
+	path := ResumePath()
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("values file already exists: %s: %w", path, fs.ErrExist)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	c.doc, c.refs = nil, nil
+	return c.SaveConfig(path)
 }
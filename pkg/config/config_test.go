@@ -1,9 +1,13 @@
 package config
 
 import (
+    "errors"
+    "io/fs"
     "os"
     "path/filepath"
+    "reflect"
     "runtime"
+    "strings"
     "testing"
 )
 
@@ -46,3 +50,117 @@ func TestEnsureTemplatesDir_WithSetMainDir(t *testing.T) {
         t.Fatalf("templates dir not created properly")
     }
 }
+
+func TestSaveConfig_RoundTripSample(t *testing.T) {
+    src := filepath.Join("..", "..", "config.yml")
+    orig, err := LoadConfig(src)
+    if err != nil {
+        t.Fatalf("LoadConfig: %v", err)
+    }
+    out := filepath.Join(t.TempDir(), "config.yml")
+    if err := orig.SaveConfig(out); err != nil {
+        t.Fatalf("SaveConfig: %v", err)
+    }
+    again, err := LoadConfig(out)
+    if err != nil {
+        t.Fatalf("LoadConfig after save: %v", err)
+    }
+    if !reflect.DeepEqual(orig.Resume, again.Resume) {
+        t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", again.Resume, orig.Resume)
+    }
+}
+
+func TestSaveConfig_PreservesCommentsAndOrder(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "resume.yml")
+    src := "# personal details\nrole_to_apply_to: \"Engineer\"\nname: \"Jane\" # full name\nskills:\n  - Go\n"
+    if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    cfg, err := LoadConfig(path)
+    if err != nil {
+        t.Fatalf("LoadConfig: %v", err)
+    }
+    cfg.Resume.Name = "Jane Doe"
+    cfg.Resume.Skills = append(cfg.Resume.Skills, "SQL")
+    if err := cfg.SaveConfig(path); err != nil {
+        t.Fatalf("SaveConfig: %v", err)
+    }
+    b, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    got := string(b)
+    for _, want := range []string{"# personal details", "name: \"Jane Doe\" # full name", "- SQL"} {
+        if !strings.Contains(got, want) {
+            t.Fatalf("expected output to contain %q, got:\n%s", want, got)
+        }
+    }
+    if strings.Index(got, "role_to_apply_to") > strings.Index(got, "name:") {
+        t.Fatalf("expected original key order to be kept, got:\n%s", got)
+    }
+    if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+        t.Fatalf("expected file mode to be kept, got %v (%v)", fi.Mode(), err)
+    }
+}
+
+func TestSaveConfig_OmitsNewEmptyKeys(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "resume.yml")
+    src := "name: Jane\nphone: \"\"\n"
+    if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    cfg, err := LoadConfig(path)
+    if err != nil {
+        t.Fatalf("LoadConfig: %v", err)
+    }
+    cfg.Resume.Experience = append(cfg.Resume.Experience, Experience{Company: "ACME"})
+    if err := cfg.SaveConfig(path); err != nil {
+        t.Fatalf("SaveConfig: %v", err)
+    }
+    b, _ := os.ReadFile(path)
+    got := string(b)
+    if !strings.Contains(got, "phone: \"\"") {
+        t.Fatalf("expected the existing empty key to be kept, got:\n%s", got)
+    }
+    for _, unwanted := range []string{"email:", "projects:", "position:", "end_date:"} {
+        if strings.Contains(got, unwanted) {
+            t.Fatalf("expected no new empty %s key, got:\n%s", unwanted, got)
+        }
+    }
+
+    fresh := filepath.Join(t.TempDir(), "resume.yml")
+    if err := (&Config{Resume: Resume{Name: "Jane"}}).SaveConfig(fresh); err != nil {
+        t.Fatalf("SaveConfig: %v", err)
+    }
+    b, _ = os.ReadFile(fresh)
+    if strings.Contains(string(b), `""`) || strings.Contains(string(b), "[]") {
+        t.Fatalf("expected a new file without empty keys, got:\n%s", b)
+    }
+}
+
+func TestCreateConfig_ScaffoldsValuesFile(t *testing.T) {
+    if err := SetMainDir(t.TempDir()); err != nil {
+        t.Fatalf("SetMainDir: %v", err)
+    }
+    cfg := &Config{Resume: Resume{Name: "Jane Doe", Email: "jane@example.com"}}
+    if err := cfg.CreateConfig(); err != nil {
+        t.Fatalf("CreateConfig: %v", err)
+    }
+    loaded, err := LoadConfig(ResumePath())
+    if err != nil {
+        t.Fatalf("LoadConfig: %v", err)
+    }
+    if loaded.Resume.Name != "Jane Doe" || loaded.Resume.Email != "jane@example.com" {
+        t.Fatalf("unexpected resume: %+v", loaded.Resume)
+    }
+    if loaded.Path() != ResumePath() {
+        t.Fatalf("unexpected path: %q", loaded.Path())
+    }
+    loaded.Resume.Skills = []string{"Go"}
+    if err := loaded.Save(); err != nil {
+        t.Fatalf("Save: %v", err)
+    }
+    if err := cfg.CreateConfig(); !errors.Is(err, fs.ErrExist) {
+        t.Fatalf("expected ErrExist on second create, got %v", err)
+    }
+}
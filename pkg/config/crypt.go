@@ -0,0 +1,198 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// encryptedMagic starts the first line of an encrypted values file. The rest
+// of that line names the key derivation and its parameters; the following
+// lines hold the base64 encoded salt, nonce and AES-256-GCM ciphertext.
+const encryptedMagic = "covlet-encrypted v1"
+
+// pbkdf2Iterations is the PBKDF2-SHA256 work factor for newly encrypted
+// files; the count used is recorded in each file. Replaced in tests.
+var pbkdf2Iterations = 600_000
+
+const (
+	saltSize = 16
+	keySize  = 32
+)
+
+var (
+	// ErrPassphraseRequired is returned when reading an encrypted values file
+	// before SetPassphrase has been called.
+	ErrPassphraseRequired = errors.New("values file is encrypted; a passphrase is required")
+	// ErrWrongPassphrase is returned when an encrypted values file does not
+	// decrypt with the passphrase, or has been tampered with.
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted encrypted values file")
+)
+
+// passphrase unlocks encrypted values files for this run; keys caches the
+// keys derived from it by salt, as derivation is deliberately slow.
+var (
+	passphrase string
+	keysMu     sync.Mutex
+	keys       = map[string][]byte{}
+)
+
+// SetPassphrase sets the passphrase used to read and write encrypted values
+// files, e.g. from the GUI unlock prompt or a --passphrase-file flag.
+func SetPassphrase(p string) {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	passphrase = p
+	keys = map[string][]byte{}
+}
+
+// HasPassphrase reports whether a passphrase has been set.
+func HasPassphrase() bool {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	return passphrase != ""
+}
+
+// ReadPassphraseFile reads a passphrase from the first line of a file.
+func ReadPassphraseFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	p, _, _ := strings.Cut(string(b), "\n")
+	p = strings.TrimSuffix(p, "\r")
+	if p == "" {
+		return "", fmt.Errorf("passphrase file %s is empty", path)
+	}
+	return p, nil
+}
+
+// IsEncrypted reports whether data is an encrypted values file.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedMagic))
+}
+
+// IsEncryptedFile reports whether the file at path is an encrypted values file.
+func IsEncryptedFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	head := make([]byte, len(encryptedMagic))
+	n, _ := f.Read(head)
+	return IsEncrypted(head[:n]), nil
+}
+
+// readValuesFile reads a values file, decrypting it when it is encrypted.
+func readValuesFile(path string) (data []byte, encrypted bool, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil || !IsEncrypted(b) {
+		return b, false, err
+	}
+	plain, err := decrypt(b)
+	if err != nil {
+		return nil, true, fmt.Errorf("%s: %w", path, err)
+	}
+	return plain, true, nil
+}
+
+// encrypt seals plain with a key derived from the passphrase.
+func encrypt(plain []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("%s pbkdf2-sha256 %d", encryptedMagic, pbkdf2Iterations)
+	gcm, err := newGCM(salt, pbkdf2Iterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nil, nonce, plain, []byte(header))
+
+	var buf bytes.Buffer
+	buf.WriteString(header + "\n")
+	for _, part := range [][]byte{salt, nonce} {
+		buf.WriteString(base64.StdEncoding.EncodeToString(part) + "\n")
+	}
+	body := base64.StdEncoding.EncodeToString(sealed)
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\n")
+	return buf.Bytes(), nil
+}
+
+// decrypt opens an encrypted values file.
+func decrypt(data []byte) ([]byte, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 4 {
+		return nil, errors.New("truncated encrypted values file")
+	}
+	header := strings.TrimSpace(lines[0])
+	fields := strings.Fields(strings.TrimPrefix(header, encryptedMagic))
+	if len(fields) != 2 || fields[0] != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported encryption %q", header)
+	}
+	iter, err := strconv.Atoi(fields[1])
+	if err != nil || iter <= 0 {
+		return nil, fmt.Errorf("invalid iteration count in %q", header)
+	}
+	salt, err1 := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	nonce, err2 := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[2]))
+	sealed, err3 := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(strings.Join(lines[3:], "")), ""))
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, fmt.Errorf("malformed encrypted values file: %w", err)
+	}
+
+	gcm, err := newGCM(salt, iter)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("malformed encrypted values file: bad nonce")
+	}
+	plain, err := gcm.Open(nil, nonce, sealed, []byte(header))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plain, nil
+}
+
+func newGCM(salt []byte, iter int) (cipher.AEAD, error) {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	cacheKey := fmt.Sprintf("%x/%d", salt, iter)
+	key, ok := keys[cacheKey]
+	if !ok {
+		var err error
+		key, err = pbkdf2.Key(sha256.New, passphrase, salt, iter, keySize)
+		if err != nil {
+			return nil, err
+		}
+		keys[cacheKey] = key
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
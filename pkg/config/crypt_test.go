@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withPassphrase sets a passphrase and a cheap key derivation for one test.
+func withPassphrase(t *testing.T, p string) {
+	t.Helper()
+	iter := pbkdf2Iterations
+	pbkdf2Iterations = 1000
+	SetPassphrase(p)
+	t.Cleanup(func() {
+		pbkdf2Iterations = iter
+		SetPassphrase("")
+	})
+}
+
+func TestSaveConfig_Encrypted(t *testing.T) {
+	withPassphrase(t, "correct horse")
+	path := filepath.Join(t.TempDir(), "resume.yml")
+	if err := os.WriteFile(path, []byte("# contact\nname: Jane Doe\nemail: jane@example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetEncrypted(true); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Resume.Phone = "+1 555 000 0000"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	b, _ := os.ReadFile(path)
+	if !IsEncrypted(b) || strings.Contains(string(b), "Jane") {
+		t.Fatalf("expected an encrypted file, got:\n%s", b)
+	}
+	if ok, err := IsEncryptedFile(path); err != nil || !ok {
+		t.Fatalf("IsEncryptedFile = %v, %v", ok, err)
+	}
+
+	again, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if !again.Encrypted() || again.Resume.Name != "Jane Doe" || again.Resume.Phone != "+1 555 000 0000" {
+		t.Fatalf("unexpected config after reload: %+v", again.Resume)
+	}
+	if diags, err := ValidateFile(path); err != nil || HasErrors(diags) {
+		t.Fatalf("ValidateFile: %v %v", diags, err)
+	}
+
+	// saving again keeps the encryption and the comments inside it
+	if err := again.Save(); err != nil {
+		t.Fatal(err)
+	}
+	plain, _, err := readValuesFile(path)
+	if err != nil || !strings.Contains(string(plain), "# contact\nname: Jane Doe\n") {
+		t.Fatalf("expected the comment to survive, got %q, %v", plain, err)
+	}
+
+	// and turning it off writes plain text again
+	if err := again.SetEncrypted(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := again.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(path)
+	if IsEncrypted(b) || !strings.Contains(string(b), "name: Jane Doe") {
+		t.Fatalf("expected plain text, got:\n%s", b)
+	}
+}
+
+func TestLoadConfig_EncryptedErrors(t *testing.T) {
+	withPassphrase(t, "correct horse")
+	path := filepath.Join(t.TempDir(), "resume.yml")
+	cfg := &Config{Resume: Resume{Name: "Jane Doe"}}
+	if err := cfg.SetEncrypted(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SaveConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	SetPassphrase("wrong")
+	if _, err := LoadConfig(path); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+	SetPassphrase("")
+	if _, err := LoadConfig(path); !errors.Is(err, ErrPassphraseRequired) {
+		t.Fatalf("expected ErrPassphraseRequired, got %v", err)
+	}
+	if err := cfg.SetEncrypted(true); !errors.Is(err, ErrPassphraseRequired) {
+		t.Fatalf("expected SetEncrypted to need a passphrase, got %v", err)
+	}
+
+	// a modified file does not decrypt
+	SetPassphrase("correct horse")
+	b, _ := os.ReadFile(path)
+	tampered := strings.Replace(string(b), "pbkdf2-sha256 1000", "pbkdf2-sha256 1001", 1)
+	if err := os.WriteFile(path, []byte(tampered), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected a tampered header to fail, got %v", err)
+	}
+}
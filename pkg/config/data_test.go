@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResumeData_Precedence(t *testing.T) {
+	r := Resume{
+		Name:   "Typed",
+		Custom: map[string]any{"Name": "Custom", "HiringManager": "Ada", "PostingURL": "custom"},
+		Extra:  map[string]any{"HiringManager": "Extra", "PostingURL": "extra", "JobReferenceNumber": "42"},
+	}
+	data := r.Data()
+	cases := map[string]any{
+		"Name":               "Typed",
+		"HiringManager":      "Ada",
+		"PostingURL":         "custom",
+		"JobReferenceNumber": "42",
+	}
+	for k, want := range cases {
+		if got := data[k]; got != want {
+			t.Fatalf("data[%q] = %v; want %v", k, got, want)
+		}
+	}
+	if _, ok := data["Extra"]; ok {
+		t.Fatalf("Extra should not be exposed as a template key")
+	}
+}
+
+func TestLoadConfig_CustomAndExtraKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resume.yml")
+	src := "name: Jane\nPostingURL: https://example.com/job\ncustom:\n  HiringManager: Ada Lovelace\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	data := cfg.Resume.Data()
+	if data["HiringManager"] != "Ada Lovelace" || data["PostingURL"] != "https://example.com/job" {
+		t.Fatalf("unexpected data: %v", data)
+	}
+	if err := cfg.SaveConfig(path); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	again, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig after save: %v", err)
+	}
+	if again.Resume.Extra["PostingURL"] != "https://example.com/job" || again.Resume.Custom["HiringManager"] != "Ada Lovelace" {
+		t.Fatalf("free-form keys lost on save: %+v", again.Resume)
+	}
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// presentWords are accepted in place of an end date for ongoing entries.
+var presentWords = []string{"present", "current", "now", "ongoing"}
+
+// dateLayouts are the forms accepted for resume dates, most specific first.
+var dateLayouts = []struct {
+	layout    string
+	precision datePrecision
+}{
+	{"2006-01-02", precisionDay},
+	{"2006-01", precisionMonth},
+	{"01/2006", precisionMonth},
+	{"1/2006", precisionMonth},
+	{"January 2006", precisionMonth},
+	{"Jan 2006", precisionMonth},
+	{"Jan. 2006", precisionMonth},
+	{"January 2, 2006", precisionDay},
+	{"Jan 2, 2006", precisionDay},
+	{"2006", precisionYear},
+}
+
+// DateLayouts names layouts that can be passed to Date.Format and the
+// formatDate template helper in place of a Go layout.
+var DateLayouts = map[string]string{
+	"year":  "2006",
+	"short": "Jan 2006",
+	"long":  "January 2006",
+	"iso":   "2006-01",
+	"full":  "January 2, 2006",
+}
+
+type datePrecision int
+
+const (
+	precisionYear datePrecision = iota + 1
+	precisionMonth
+	precisionDay
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+// Date is a resume date as written in a values file: a year ("2018"), a month
+// ("June 2022", "2022-06", "06/2022"), an ISO day ("2022-06-15") or "Present".
+// It keeps the original text, so files round-trip exactly as written, and
+// parses it for formatting, durations and sorting. Text that cannot be parsed
+// is kept as is; Validate reports it.
+type Date struct {
+	raw       string
+	t         time.Time
+	present   bool
+	precision datePrecision
+}
+
+// NewDate returns the Date for s, which need not be parseable.
+func NewDate(s string) Date {
+	d, _ := ParseDate(s)
+	return d
+}
+
+// ParseDate parses s into a Date. On error the returned Date still holds s.
+func ParseDate(s string) (Date, error) {
+	d := Date{raw: s}
+	if strings.TrimSpace(s) == "" {
+		return d, nil
+	}
+	t, present, prec, err := parseDate(s)
+	if err != nil {
+		return d, err
+	}
+	d.t, d.present, d.precision = t, present, prec
+	return d, nil
+}
+
+// String returns the date as written.
+func (d Date) String() string { return d.raw }
+
+// IsZero reports whether no date was given.
+func (d Date) IsZero() bool { return strings.TrimSpace(d.raw) == "" }
+
+// IsPresent reports an ongoing date such as "Present".
+func (d Date) IsPresent() bool { return d.present }
+
+// Valid reports whether the date was given and could be parsed.
+func (d Date) Valid() bool { return d.present || d.precision != 0 }
+
+// Time returns the start of the period the date names; "Present" is the
+// current time and an invalid date is the zero time.
+func (d Date) Time() time.Time {
+	if d.present {
+		return now()
+	}
+	return d.t
+}
+
+// Format renders the date with a Go layout or a name from DateLayouts.
+// A year-only date always renders as the year, "Present" renders as
+// "Present" and an unparseable date renders as written.
+func (d Date) Format(layout string) string {
+	switch {
+	case d.present:
+		return "Present"
+	case !d.Valid():
+		return d.raw
+	case d.precision == precisionYear:
+		return d.t.Format("2006")
+	}
+	if named, ok := DateLayouts[layout]; ok {
+		layout = named
+	}
+	return d.t.Format(layout)
+}
+
+// ISO renders the date in ISO 8601 at its precision: "2022", "2022-06" or
+// "2022-06-15". It is empty for "Present", an empty date and a date that
+// cannot be parsed.
+func (d Date) ISO() string {
+	if d.present || !d.Valid() {
+		return ""
+	}
+	switch d.precision {
+	case precisionYear:
+		return d.t.Format("2006")
+	case precisionMonth:
+		return d.t.Format("2006-01")
+	}
+	return d.t.Format("2006-01-02")
+}
+
+// MarshalText returns the date as written, so values files keep their text.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.raw), nil
+}
+
+// UnmarshalText accepts any text; unparseable dates are kept for Validate to report.
+func (d *Date) UnmarshalText(b []byte) error {
+	*d = NewDate(string(b))
+	return nil
+}
+
+// Duration describes the time between two dates, e.g. "2 years 3 months".
+// An empty end date counts as ongoing.
+func Duration(start, end Date) string {
+	if !start.Valid() || start.present {
+		return ""
+	}
+	to := end
+	if end.IsZero() {
+		to = Date{present: true}
+	}
+	if !to.Valid() {
+		return ""
+	}
+	a, b := start.Time(), to.Time()
+	months := (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+	if months < 0 {
+		return ""
+	}
+	years, months := months/12, months%12
+	var parts []string
+	if years > 0 {
+		parts = append(parts, pluralUnit(years, "year"))
+	}
+	if months > 0 {
+		parts = append(parts, pluralUnit(months, "month"))
+	}
+	if len(parts) == 0 {
+		return "less than a month"
+	}
+	return strings.Join(parts, " ")
+}
+
+func pluralUnit(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// Duration describes how long the position was held.
+func (e Experience) Duration() string { return Duration(e.StartDate, e.EndDate) }
+
+// Duration describes how long the studies took.
+func (e Education) Duration() string { return Duration(e.StartDate, e.EndDate) }
+
+// SortExperience returns a copy of list in reverse-chronological order: ongoing
+// positions first, then by end date and start date, most recent first.
+// Entries with unparseable dates keep their relative order at the end.
+func SortExperience(list []Experience) []Experience {
+	out := append([]Experience(nil), list...)
+	key := func(e Experience) (time.Time, time.Time, bool) {
+		end := e.EndDate
+		if end.IsZero() && e.StartDate.Valid() {
+			end = Date{present: true}
+		}
+		return end.Time(), e.StartDate.Time(), end.Valid()
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		ei, si, oki := key(out[i])
+		ej, sj, okj := key(out[j])
+		if oki != okj {
+			return oki
+		}
+		if !ei.Equal(ej) {
+			return ei.After(ej)
+		}
+		return si.After(sj)
+	})
+	return out
+}
+
+// parseFlexibleDate parses a resume date such as "2018", "June 2022",
+// "2022-06" or "Present". present reports an ongoing date.
+func parseFlexibleDate(s string) (t time.Time, present bool, err error) {
+	t, present, _, err = parseDate(s)
+	return t, present, err
+}
+
+func parseDate(s string) (time.Time, bool, datePrecision, error) {
+	s = strings.TrimSpace(s)
+	for _, w := range presentWords {
+		if strings.EqualFold(s, w) {
+			return time.Time{}, true, 0, nil
+		}
+	}
+	// month names are matched case-insensitively
+	for _, in := range []string{s, titleWords(s)} {
+		for _, l := range dateLayouts {
+			if t, err := time.Parse(l.layout, in); err == nil {
+				return t, false, l.precision, nil
+			}
+		}
+	}
+	return time.Time{}, false, 0, fmt.Errorf("unrecognized date %q (use e.g. 2022, June 2022, 2022-06 or Present)", s)
+}
+
+// titleWords upper-cases the first letter of each word and lower-cases the rest.
+func titleWords(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
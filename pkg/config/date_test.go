@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDate_Format(t *testing.T) {
+	cases := []struct {
+		in, layout, want string
+	}{
+		{"June 2022", "short", "Jun 2022"},
+		{"2022-06", "long", "June 2022"},
+		{"06/2022", "2006-01", "2022-06"},
+		{"2018", "long", "2018"},
+		{"2022-06-15", "full", "June 15, 2022"},
+		{"Present", "long", "Present"},
+		{"someday", "long", "someday"},
+	}
+	for _, c := range cases {
+		if got := NewDate(c.in).Format(c.layout); got != c.want {
+			t.Fatalf("NewDate(%q).Format(%q) = %q; want %q", c.in, c.layout, got, c.want)
+		}
+	}
+	if _, err := ParseDate("someday"); err == nil {
+		t.Fatalf("expected ParseDate to reject an unrecognized date")
+	}
+}
+
+func TestDate_YAMLRoundTripKeepsText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resume.yml")
+	src := "experience:\n  - company: Go Corp\n    start_date: jun 2022\n    end_date: Present\n  - company: Test Inc.\n    start_date: 2018\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	ex := cfg.Resume.Experience
+	if !ex[0].StartDate.Valid() || ex[0].StartDate.Time().Month() != time.June || !ex[0].EndDate.IsPresent() {
+		t.Fatalf("unexpected parsed dates: %+v", ex[0])
+	}
+	if err := cfg.SaveConfig(path); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"start_date: jun 2022", "end_date: Present", "start_date: 2018"} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("expected %q to be written as is, got:\n%s", want, b)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, time.September, 10, 0, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+	cases := []struct {
+		start, end, want string
+	}{
+		{"June 2022", "Present", "2 years 3 months"},
+		{"June 2022", "", "2 years 3 months"},
+		{"May 2021", "August 2021", "3 months"},
+		{"2018", "2022", "4 years"},
+		{"2023-01", "2024-01", "1 year"},
+		{"May 2021", "May 2021", "less than a month"},
+		{"August 2021", "May 2021", ""},
+		{"someday", "2022", ""},
+	}
+	for _, c := range cases {
+		if got := Duration(NewDate(c.start), NewDate(c.end)); got != c.want {
+			t.Fatalf("Duration(%q, %q) = %q; want %q", c.start, c.end, got, c.want)
+		}
+	}
+}
+
+func TestSortExperience(t *testing.T) {
+	in := []Experience{
+		{Company: "Intern", StartDate: NewDate("May 2019"), EndDate: NewDate("August 2019")},
+		{Company: "Unknown", StartDate: NewDate("someday")},
+		{Company: "Current", StartDate: NewDate("June 2022"), EndDate: NewDate("Present")},
+		{Company: "Previous", StartDate: NewDate("2020"), EndDate: NewDate("May 2022")},
+	}
+	got := SortExperience(in)
+	var names []string
+	for _, e := range got {
+		names = append(names, e.Company)
+	}
+	if strings.Join(names, ",") != "Current,Previous,Intern,Unknown" {
+		t.Fatalf("unexpected order: %v", names)
+	}
+	if in[0].Company != "Intern" {
+		t.Fatalf("SortExperience modified its input")
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the file format of a values file.
+type Format int
+
+const (
+	FormatYAML Format = iota
+	FormatJSON
+	FormatTOML
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatJSON:
+		return "json"
+	case FormatTOML:
+		return "toml"
+	}
+	return "yaml"
+}
+
+// ValuesExtensions are the file extensions recognized as values files.
+var ValuesExtensions = []string{".yml", ".yaml", ".json", ".toml"}
+
+// FormatOf detects the format of a values file from its extension; anything
+// other than .json or .toml is YAML.
+func FormatOf(filename string) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	}
+	return FormatYAML
+}
+
+// IsValuesFile reports whether filename has one of the ValuesExtensions.
+func IsValuesFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, e := range ValuesExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDocument parses a values file of format f into a YAML document node,
+// so every format shares decoding, validation and merge-on-save. Node lines
+// and columns point into data. JSON is parsed as YAML, of which it is a subset.
+func parseDocument(data []byte, f Format) (*yaml.Node, error) {
+	if f == FormatTOML {
+		return parseTOML(data)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// encodeDocument renders a document node in format f.
+func encodeDocument(doc *yaml.Node, f Format) ([]byte, error) {
+	switch f {
+	case FormatJSON:
+		var buf bytes.Buffer
+		if err := writeJSONNode(&buf, documentRoot(doc), ""); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	case FormatTOML:
+		return encodeTOML(doc)
+	}
+	return encodeNode(doc)
+}
+
+// documentRoot returns the top-level value of a document node.
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// writeJSONNode writes n as indented JSON, keeping the key order of mappings.
+func writeJSONNode(buf *bytes.Buffer, n *yaml.Node, indent string) error {
+	inner := indent + "  "
+	switch n.Kind {
+	case yaml.AliasNode:
+		return writeJSONNode(buf, n.Alias, indent)
+	case yaml.MappingNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			buf.WriteString(inner)
+			buf.WriteString(strconv.Quote(n.Content[i].Value))
+			buf.WriteString(": ")
+			if err := writeJSONNode(buf, n.Content[i+1], inner); err != nil {
+				return err
+			}
+			if i+2 < len(n.Content) {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "}")
+	case yaml.SequenceNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range n.Content {
+			buf.WriteString(inner)
+			if err := writeJSONNode(buf, item, inner); err != nil {
+				return err
+			}
+			if i+1 < len(n.Content) {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "]")
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!null":
+			buf.WriteString("null")
+		case "!!bool", "!!int", "!!float":
+			var v any
+			if err := n.Decode(&v); err != nil {
+				return err
+			}
+			fmt.Fprint(buf, v)
+		default:
+			buf.WriteString(jsonQuote(n.Value))
+		}
+	default:
+		return fmt.Errorf("cannot write %v node as JSON", n.Kind)
+	}
+	return nil
+}
+
+// jsonQuote quotes s as a JSON (and TOML basic) string.
+func jsonQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
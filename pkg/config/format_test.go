@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const formatYAML = `name: Jane Doe
+email: jane@example.com
+skills: [Go, SQL]
+experience:
+  - company: ACME
+    position: Engineer
+    start_date: 2019-06
+    responsibilities: [Built the ledger]
+custom:
+  HiringManager: Sam
+`
+
+const formatJSON = `{
+  "name": "Jane Doe",
+  "email": "jane@example.com",
+  "skills": ["Go", "SQL"],
+  "experience": [
+    {
+      "company": "ACME",
+      "position": "Engineer",
+      "start_date": "2019-06",
+      "responsibilities": ["Built the ledger"]
+    }
+  ],
+  "custom": {"HiringManager": "Sam"}
+}
+`
+
+const formatTOML = `name = "Jane Doe"
+email = "jane@example.com"
+skills = ["Go", "SQL"]
+
+[[experience]]
+company = "ACME"
+position = "Engineer"
+start_date = "2019-06"
+responsibilities = ["Built the ledger"]
+
+[custom]
+HiringManager = "Sam"
+`
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]Format{
+		"resume.yml":   FormatYAML,
+		"resume.YAML":  FormatYAML,
+		"resume.json":  FormatJSON,
+		"a/b/x.TOML":   FormatTOML,
+		"no-extension": FormatYAML,
+	}
+	for name, want := range tests {
+		if got := FormatOf(name); got != want {
+			t.Errorf("FormatOf(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestLoadConfig_Formats(t *testing.T) {
+	dir := t.TempDir()
+	load := func(name, content string) Resume {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig(%s): %v", name, err)
+		}
+		return cfg.Resume
+	}
+	want := load("resume.yml", formatYAML)
+	for name, content := range map[string]string{"resume.json": formatJSON, "resume.toml": formatTOML} {
+		if got := load(name, content); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s decoded differently:\n got %+v\nwant %+v", name, got, want)
+		}
+	}
+}
+
+func TestSaveConfig_KeepsFormatAndOrder(t *testing.T) {
+	for name, content := range map[string]string{"resume.json": formatJSON, "resume.toml": formatTOML} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadConfig(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.Resume.Phone = "+1 555 000 0000"
+			cfg.Resume.Experience[0].EndDate = NewDate("Present")
+			if err := cfg.Save(); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := string(b)
+			if FormatOf(name) == FormatJSON && !strings.HasPrefix(strings.TrimSpace(out), "{") {
+				t.Fatalf("expected JSON output, got:\n%s", out)
+			}
+			if i, j := strings.Index(out, "name"), strings.Index(out, "email"); i < 0 || j < i {
+				t.Fatalf("expected name before email, got:\n%s", out)
+			}
+
+			again, err := LoadConfig(path)
+			if err != nil {
+				t.Fatalf("reload: %v\n%s", err, out)
+			}
+			got := again.Resume
+			if got.Name != "Jane Doe" || got.Phone != "+1 555 000 0000" || got.Custom["HiringManager"] != "Sam" {
+				t.Fatalf("unexpected values after reload: %+v\n%s", got, out)
+			}
+			if ex := got.Experience[0]; ex.StartDate.String() != "2019-06" || !ex.EndDate.IsPresent() || len(ex.Responsibilities) != 1 {
+				t.Fatalf("unexpected experience after reload: %+v\n%s", ex, out)
+			}
+		})
+	}
+}
+
+func TestValidateFormat_Positions(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		data    string
+		path    string
+		line    int
+		message string
+	}{
+		{
+			name:   "json unknown key",
+			format: FormatJSON,
+			data:   "{\n  \"name\": \"Jane\",\n  \"email\": \"jane@example.com\",\n  \"experience\": [\n    {\"compnay\": \"ACME\", \"position\": \"Engineer\"}\n  ]\n}\n",
+			path:   "experience.0.compnay",
+			line:   5,
+		},
+		{
+			name:   "toml bad email",
+			format: FormatTOML,
+			data:   "name = \"Jane\"\n\n# contact\nemail = \"not-an-email\"\n",
+			path:   "email",
+			line:   4,
+		},
+		{
+			name:   "toml entry in array of tables",
+			format: FormatTOML,
+			data:   "name = \"Jane\"\nemail = \"jane@example.com\"\n\n[[experience]]\ncompany = \"A\"\nposition = \"B\"\n\n[[experience]]\nposition = \"C\"\n",
+			path:   "experience.1.company",
+			line:   8,
+		},
+		{
+			name:    "toml syntax error",
+			format:  FormatTOML,
+			data:    "name = \"Jane\"\nemail = \n",
+			line:    2,
+			message: "expected value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := ValidateFormat([]byte(tt.data), tt.format)
+			for _, d := range diags {
+				if d.Path == tt.path && d.Line == tt.line && strings.Contains(strings.ToLower(d.Message), tt.message) {
+					return
+				}
+			}
+			t.Fatalf("no diagnostic for %q at line %d; got %v", tt.path, tt.line, diags)
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSONResume is the part of the jsonresume.org schema Covlet maps onto Resume.
+// Keys outside it are reported as unmapped by ImportJSONResume.
+type JSONResume struct {
+	Basics    JSONBasics      `json:"basics"`
+	Work      []JSONWork      `json:"work,omitempty"`
+	Education []JSONEducation `json:"education,omitempty"`
+	Projects  []JSONProject   `json:"projects,omitempty"`
+	Skills    []JSONSkill     `json:"skills,omitempty"`
+}
+
+// JSONBasics is the "basics" section of a JSON Resume.
+type JSONBasics struct {
+	Name     string        `json:"name,omitempty"`
+	Label    string        `json:"label,omitempty"`
+	Email    string        `json:"email,omitempty"`
+	Phone    string        `json:"phone,omitempty"`
+	URL      string        `json:"url,omitempty"`
+	Summary  string        `json:"summary,omitempty"`
+	Location JSONLocation  `json:"location,omitempty"`
+	Profiles []JSONProfile `json:"profiles,omitempty"`
+}
+
+// JSONLocation is a postal address in a JSON Resume.
+type JSONLocation struct {
+	Address     string `json:"address,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	City        string `json:"city,omitempty"`
+	Region      string `json:"region,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+// JSONProfile is a social network profile in a JSON Resume.
+type JSONProfile struct {
+	Network  string `json:"network,omitempty"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// JSONWork is a "work" entry of a JSON Resume.
+type JSONWork struct {
+	Name       string   `json:"name,omitempty"`
+	Position   string   `json:"position,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+// JSONEducation is an "education" entry of a JSON Resume.
+type JSONEducation struct {
+	Institution string `json:"institution,omitempty"`
+	StudyType   string `json:"studyType,omitempty"`
+	Area        string `json:"area,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+	Score       string `json:"score,omitempty"`
+}
+
+// JSONProject is a "projects" entry of a JSON Resume.
+type JSONProject struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// JSONSkill is a "skills" entry of a JSON Resume: a skill or a named group of keywords.
+type JSONSkill struct {
+	Name     string   `json:"name,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// ImportJSONResumeFile reads a jsonresume.org resume.json; see ImportJSONResume.
+func ImportJSONResumeFile(filename string) (Resume, []string, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return Resume{}, nil, err
+	}
+	r, unmapped, err := ImportJSONResume(b)
+	if err != nil {
+		return Resume{}, nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return r, unmapped, nil
+}
+
+// ImportJSONResume maps a jsonresume.org document onto a Resume. It also
+// returns the paths of fields that hold data but have no place in Resume,
+// e.g. "work[0].summary" or "awards", so callers can tell the user what was
+// left behind. The basics label and summary become the custom variables
+// Label and Summary; skill groups contribute their keywords, or their name
+// when they have none.
+func ImportJSONResume(data []byte) (Resume, []string, error) {
+	var jr JSONResume
+	if err := json.Unmarshal(data, &jr); err != nil {
+		return Resume{}, nil, fmt.Errorf("invalid JSON Resume: %w", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Resume{}, nil, fmt.Errorf("invalid JSON Resume: %w", err)
+	}
+	var unmapped []string
+	collectUnmapped(raw, reflect.TypeOf(jr), "", &unmapped)
+
+	b := jr.Basics
+	r := Resume{
+		Name:    b.Name,
+		Email:   b.Email,
+		Phone:   b.Phone,
+		Address: b.Location.String(),
+		Website: b.URL,
+	}
+	for i, p := range b.Profiles {
+		if strings.EqualFold(p.Network, "github") && r.Github == "" {
+			r.Github = p.URL
+			if r.Github == "" && p.Username != "" {
+				r.Github = "https://github.com/" + p.Username
+			}
+			continue
+		}
+		unmapped = append(unmapped, fmt.Sprintf("basics.profiles[%d]", i))
+	}
+	for k, v := range map[string]string{"Label": b.Label, "Summary": b.Summary} {
+		if v != "" {
+			if r.Custom == nil {
+				r.Custom = map[string]any{}
+			}
+			r.Custom[k] = v
+		}
+	}
+
+	for _, w := range jr.Work {
+		r.Experience = append(r.Experience, Experience{
+			Company:          w.Name,
+			Position:         w.Position,
+			StartDate:        NewDate(w.StartDate),
+			EndDate:          NewDate(w.EndDate),
+			Responsibilities: w.Highlights,
+		})
+	}
+	for _, ed := range jr.Education {
+		degree := ed.StudyType
+		switch {
+		case degree == "":
+			degree = ed.Area
+		case ed.Area != "":
+			degree += " in " + ed.Area
+		}
+		r.Education = append(r.Education, Education{
+			Institution: ed.Institution,
+			Degree:      degree,
+			StartDate:   NewDate(ed.StartDate),
+			EndDate:     NewDate(ed.EndDate),
+			GPA:         ed.Score,
+		})
+	}
+	for _, p := range jr.Projects {
+		r.Projects = append(r.Projects, Project{Name: p.Name, Description: p.Description, URL: p.URL})
+	}
+	for _, s := range jr.Skills {
+		if len(s.Keywords) == 0 {
+			if s.Name != "" {
+				r.Skills = append(r.Skills, s.Name)
+			}
+			continue
+		}
+		r.Skills = append(r.Skills, s.Keywords...)
+	}
+
+	sort.Strings(unmapped)
+	return r, unmapped, nil
+}
+
+// String joins the non-empty parts of the location into a one-line address.
+func (l JSONLocation) String() string {
+	cityLine := strings.TrimSpace(strings.Join(nonEmpty(l.Region, l.PostalCode), " "))
+	return strings.Join(nonEmpty(l.Address, l.City, cityLine, l.CountryCode), ", ")
+}
+
+// collectUnmapped appends the paths of non-empty values in raw that have no
+// matching json field in t.
+func collectUnmapped(raw any, t reflect.Type, path string, out *[]string) {
+	if isEmptyJSON(raw) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := raw.(map[string]any)
+		if !ok {
+			*out = append(*out, path)
+			return
+		}
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			fields[name] = t.Field(i).Type
+		}
+		for k, v := range m {
+			ft, ok := fields[k]
+			if !ok {
+				if !isEmptyJSON(v) {
+					*out = append(*out, joinPath(path, k))
+				}
+				continue
+			}
+			collectUnmapped(v, ft, joinPath(path, k), out)
+		}
+	case reflect.Slice:
+		list, ok := raw.([]any)
+		if !ok {
+			*out = append(*out, path)
+			return
+		}
+		for i, v := range list {
+			collectUnmapped(v, t.Elem(), fmt.Sprintf("%s[%d]", path, i), out)
+		}
+	}
+}
+
+func isEmptyJSON(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		for _, e := range v {
+			if !isEmptyJSON(e) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func nonEmpty(parts ...string) []string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ImportInto replaces the resume data in dst with imported. What dst knows
+// about the application at hand is kept: the company and role to apply to
+// and its custom variables, which win over imported ones of the same name.
+func ImportInto(dst, imported Resume) Resume {
+	out := imported.Clone()
+	out.CompanyToApplyTo = dst.CompanyToApplyTo
+	out.RoleToApplyTo = dst.RoleToApplyTo
+	for k, v := range dst.Custom {
+		if out.Custom == nil {
+			out.Custom = map[string]any{}
+		}
+		out.Custom[k] = v
+	}
+	for k, v := range dst.Extra {
+		if out.Extra == nil {
+			out.Extra = map[string]any{}
+		}
+		out.Extra[k] = v
+	}
+	return out
+}
+
+// ExportJSONResume converts r to a jsonresume.org document, the reverse of
+// ImportJSONResume: the custom variables Label and Summary fill the basics,
+// the address goes to the location's address line, the GitHub URL becomes a
+// profile, degrees become study types and every skill its own entry. Dates
+// are written in ISO 8601 at the precision they were given, or as written when
+// they cannot be parsed; ongoing entries have no end date.
+func ExportJSONResume(r Resume) ([]byte, error) {
+	jr := JSONResume{Basics: JSONBasics{
+		Name:     r.Name,
+		Label:    customString(r, "Label"),
+		Email:    r.Email,
+		Phone:    r.Phone,
+		URL:      r.Website,
+		Summary:  customString(r, "Summary"),
+		Location: JSONLocation{Address: r.Address},
+	}}
+	if r.Github != "" {
+		p := JSONProfile{Network: "GitHub", URL: r.Github}
+		if _, user, ok := strings.Cut(r.Github, "github.com/"); ok {
+			p.Username = strings.Trim(user, "/")
+		}
+		jr.Basics.Profiles = []JSONProfile{p}
+	}
+	for _, ex := range r.Experience {
+		jr.Work = append(jr.Work, JSONWork{
+			Name:       ex.Company,
+			Position:   ex.Position,
+			StartDate:  jsonResumeDate(ex.StartDate),
+			EndDate:    jsonResumeDate(ex.EndDate),
+			Highlights: ex.Responsibilities,
+		})
+	}
+	for _, ed := range r.Education {
+		jr.Education = append(jr.Education, JSONEducation{
+			Institution: ed.Institution,
+			StudyType:   ed.Degree,
+			StartDate:   jsonResumeDate(ed.StartDate),
+			EndDate:     jsonResumeDate(ed.EndDate),
+			Score:       ed.GPA,
+		})
+	}
+	for _, p := range r.Projects {
+		jr.Projects = append(jr.Projects, JSONProject{Name: p.Name, Description: p.Description, URL: p.URL})
+	}
+	for _, s := range r.Skills {
+		jr.Skills = append(jr.Skills, JSONSkill{Name: s})
+	}
+	return json.MarshalIndent(jr, "", "  ")
+}
+
+// jsonResumeDate is d in ISO 8601, or its text when it is not a date
+// (e.g. "Summer 2019"), so nothing is lost on export.
+func jsonResumeDate(d Date) string {
+	if !d.Valid() && !d.IsZero() {
+		return d.String()
+	}
+	return d.ISO()
+}
+
+// ExportJSON dumps r as JSON keyed, and ordered, like the YAML values file,
+// including custom variables and extra top-level keys.
+func ExportJSON(r Resume) ([]byte, error) {
+	var n yaml.Node
+	if err := n.Encode(r); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeJSONNode(&buf, &n, ""); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func customString(r Resume, key string) string {
+	s, _ := r.Custom[key].(string)
+	return s
+}
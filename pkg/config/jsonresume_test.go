@@ -0,0 +1,230 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleJSONResume = `{
+  "basics": {
+    "name": "Jane Doe",
+    "label": "Backend Engineer",
+    "email": "jane@example.com",
+    "phone": "+1 555 000 0000",
+    "url": "https://jane.dev",
+    "summary": "Builds reliable services.",
+    "location": {"address": "1 Main St", "postalCode": "94105", "city": "San Francisco", "region": "CA", "countryCode": "US"},
+    "profiles": [
+      {"network": "Twitter", "username": "jane"},
+      {"network": "GitHub", "username": "janedoe"}
+    ]
+  },
+  "work": [{
+    "name": "ACME",
+    "position": "Engineer",
+    "startDate": "2019-04",
+    "endDate": "",
+    "summary": "Payments team",
+    "highlights": ["Built the ledger", "Cut latency 40%"]
+  }],
+  "education": [{
+    "institution": "State University",
+    "studyType": "Bachelor",
+    "area": "Computer Science",
+    "startDate": "2014",
+    "endDate": "2018",
+    "score": "3.8",
+    "courses": []
+  }],
+  "projects": [{"name": "covlet", "description": "Cover letters", "url": "https://example.com/covlet", "keywords": ["go"]}],
+  "skills": [
+    {"name": "Languages", "level": "", "keywords": ["Go", "SQL"]},
+    {"name": "Kubernetes"}
+  ],
+  "awards": [{"title": "Hackathon winner"}],
+  "meta": {}
+}`
+
+func TestImportJSONResume(t *testing.T) {
+	r, unmapped, err := ImportJSONResume([]byte(sampleJSONResume))
+	if err != nil {
+		t.Fatalf("ImportJSONResume: %v", err)
+	}
+
+	if r.Name != "Jane Doe" || r.Email != "jane@example.com" || r.Website != "https://jane.dev" {
+		t.Fatalf("unexpected basics: %+v", r)
+	}
+	if want := "1 Main St, San Francisco, CA 94105, US"; r.Address != want {
+		t.Fatalf("Address = %q, want %q", r.Address, want)
+	}
+	if r.Github != "https://github.com/janedoe" {
+		t.Fatalf("Github = %q", r.Github)
+	}
+	if r.Custom["Label"] != "Backend Engineer" || r.Custom["Summary"] != "Builds reliable services." {
+		t.Fatalf("unexpected custom variables: %v", r.Custom)
+	}
+
+	if len(r.Experience) != 1 {
+		t.Fatalf("expected one experience entry, got %d", len(r.Experience))
+	}
+	ex := r.Experience[0]
+	if ex.Company != "ACME" || ex.Position != "Engineer" || ex.StartDate.String() != "2019-04" || !ex.EndDate.IsZero() {
+		t.Fatalf("unexpected experience: %+v", ex)
+	}
+	if !reflect.DeepEqual(ex.Responsibilities, []string{"Built the ledger", "Cut latency 40%"}) {
+		t.Fatalf("Responsibilities = %v", ex.Responsibilities)
+	}
+
+	ed := r.Education[0]
+	if ed.Institution != "State University" || ed.Degree != "Bachelor in Computer Science" || ed.GPA != "3.8" || ed.EndDate.String() != "2018" {
+		t.Fatalf("unexpected education: %+v", ed)
+	}
+	if p := r.Projects[0]; p.Name != "covlet" || p.URL != "https://example.com/covlet" {
+		t.Fatalf("unexpected project: %+v", p)
+	}
+	if !reflect.DeepEqual(r.Skills, []string{"Go", "SQL", "Kubernetes"}) {
+		t.Fatalf("Skills = %v", r.Skills)
+	}
+
+	want := []string{"awards", "basics.profiles[0]", "projects[0].keywords", "work[0].summary"}
+	if !reflect.DeepEqual(unmapped, want) {
+		t.Fatalf("unmapped = %v, want %v", unmapped, want)
+	}
+}
+
+func TestImportJSONResume_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"not json", "name: Jane"},
+		{"wrong type", `{"work": {"name": "ACME"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ImportJSONResume([]byte(tt.in)); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestImportInto(t *testing.T) {
+	dst := Resume{
+		Name:             "Old Name",
+		Skills:           []string{"Perl"},
+		CompanyToApplyTo: "ACME",
+		Custom:           map[string]any{"HiringManager": "Sam", "Label": "Mine"},
+	}
+	imported := Resume{Name: "Jane", Skills: []string{"Go"}, Custom: map[string]any{"Label": "Theirs", "Summary": "Hi"}}
+
+	got := ImportInto(dst, imported)
+	if got.Name != "Jane" || !reflect.DeepEqual(got.Skills, []string{"Go"}) {
+		t.Fatalf("expected imported resume data, got %+v", got)
+	}
+	if got.CompanyToApplyTo != "ACME" {
+		t.Fatalf("expected the application fields to be kept, got %q", got.CompanyToApplyTo)
+	}
+	want := map[string]any{"HiringManager": "Sam", "Label": "Mine", "Summary": "Hi"}
+	if !reflect.DeepEqual(got.Custom, want) {
+		t.Fatalf("Custom = %v, want %v", got.Custom, want)
+	}
+	if imported.Custom["Label"] != "Theirs" {
+		t.Fatalf("imported resume was modified")
+	}
+}
+
+func TestExportJSONResume_RoundTrip(t *testing.T) {
+	want, _, err := ImportJSONResume([]byte(sampleJSONResume))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ExportJSONResume(want)
+	if err != nil {
+		t.Fatalf("ExportJSONResume: %v", err)
+	}
+	got, unmapped, err := ImportJSONResume(b)
+	if err != nil {
+		t.Fatalf("re-import: %v", err)
+	}
+	if len(unmapped) != 0 {
+		t.Fatalf("exported document has unmapped fields: %v", unmapped)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestExportJSONResume_ISODates(t *testing.T) {
+	r := Resume{
+		Experience: []Experience{
+			{Company: "ACME", Position: "Engineer", StartDate: NewDate("June 2022"), EndDate: NewDate("Present")},
+			{Company: "Initech", Position: "Intern", StartDate: NewDate("Jan 15, 2020"), EndDate: NewDate("03/2021")},
+			{Company: "Camp", Position: "Counselor", StartDate: NewDate("Summer 2019")},
+		},
+		Education: []Education{{Institution: "State University", StartDate: NewDate("2014"), EndDate: NewDate("2018")}},
+	}
+	b, err := ExportJSONResume(r)
+	if err != nil {
+		t.Fatalf("ExportJSONResume: %v", err)
+	}
+	var jr JSONResume
+	if err := json.Unmarshal(b, &jr); err != nil {
+		t.Fatal(err)
+	}
+	got := [][2]string{
+		{jr.Work[0].StartDate, jr.Work[0].EndDate},
+		{jr.Work[1].StartDate, jr.Work[1].EndDate},
+		{jr.Work[2].StartDate, jr.Work[2].EndDate},
+		{jr.Education[0].StartDate, jr.Education[0].EndDate},
+	}
+	want := [][2]string{{"2022-06", ""}, {"2020-01-15", "2021-03"}, {"Summer 2019", ""}, {"2014", "2018"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("dates = %v, want %v", got, want)
+	}
+	if strings.Contains(string(b), "endDate\": \"\"") {
+		t.Fatalf("ongoing entry has an empty endDate:\n%s", b)
+	}
+
+	back, _, err := ImportJSONResume(b)
+	if err != nil {
+		t.Fatalf("re-import: %v", err)
+	}
+	for i, ex := range back.Experience {
+		if !ex.StartDate.Time().Equal(r.Experience[i].StartDate.Time()) {
+			t.Errorf("experience %d start = %v, want %v", i, ex.StartDate, r.Experience[i].StartDate)
+		}
+	}
+	if !back.Experience[0].EndDate.IsZero() {
+		t.Errorf("ongoing end date = %q, want empty", back.Experience[0].EndDate)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	r := Resume{
+		Name:       "Jane",
+		Experience: []Experience{{Company: "ACME", StartDate: NewDate("2018")}},
+		Custom:     map[string]any{"HiringManager": "Sam"},
+		Extra:      map[string]any{"posting_url": "https://example.com/job"},
+	}
+	b, err := ExportJSON(r)
+	if err != nil {
+		t.Fatalf("ExportJSON: %v", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, b)
+	}
+	if m["name"] != "Jane" || m["posting_url"] != "https://example.com/job" {
+		t.Fatalf("unexpected top-level keys: %v", m)
+	}
+	ex := m["experience"].([]any)[0].(map[string]any)
+	if ex["company"] != "ACME" || ex["start_date"] != "2018" {
+		t.Fatalf("unexpected experience entry: %v", ex)
+	}
+	if m["custom"].(map[string]any)["HiringManager"] != "Sam" {
+		t.Fatalf("expected custom variables, got %v", m["custom"])
+	}
+}
@@ -0,0 +1,401 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OverridesLayer is the layer name recorded for values set by overrides.
+const OverridesLayer = "overrides"
+
+// Layer is one values file in a render stack.
+type Layer struct {
+	// Name identifies the layer in provenance reports, e.g. "companies/acme.yml".
+	Name   string
+	Config *Config
+}
+
+// Stack is the result of merging several layers of values.
+//
+// Layers are merged in order, later layers winning:
+//   - maps are merged key by key, recursively;
+//   - lists of maps (education, experience, projects) are merged entry by
+//     entry by index, and extra entries are appended;
+//   - lists of scalars (skills, responsibilities) are appended to, skipping
+//     items already present;
+//   - scalars replace the value below them.
+//
+// Empty values in a layer never replace values from the layers below.
+type Stack struct {
+	Resume Resume
+	// Sources maps the yaml path of every leaf value (e.g. "experience.0.company")
+	// to the name of the layer it came from.
+	Sources map[string]string
+
+	values map[string]any
+}
+
+// LoadLayer loads the values file at path as a layer named relative to ValuesDir.
+func LoadLayer(path string) (Layer, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return Layer{}, err
+	}
+	return Layer{Name: LayerName(path), Config: cfg}, nil
+}
+
+// LayerName returns the display name of a values file: its path relative to
+// ValuesDir when inside it, otherwise its base name.
+func LayerName(path string) string {
+	if rel, err := filepath.Rel(ValuesDir(), path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.Base(path)
+}
+
+// MergeLayers merges the layers bottom to top into a single Resume.
+func MergeLayers(layers ...Layer) (*Stack, error) {
+	sources := map[string]string{}
+	var merged any = map[string]any{}
+	for _, l := range layers {
+		if l.Config == nil {
+			return nil, fmt.Errorf("layer %q has no config", l.Name)
+		}
+		vals, err := resumeValues(l.Config.Resume)
+		if err != nil {
+			return nil, fmt.Errorf("layer %q: %w", l.Name, err)
+		}
+		merged = mergeValues(merged, vals, "", l.Name, sources)
+	}
+	return newStack(merged.(map[string]any), sources)
+}
+
+// WithOverrides returns a copy of the stack with overrides applied on top
+// (see ApplyOverrides); their paths are attributed to OverridesLayer.
+func (s *Stack) WithOverrides(overrides map[string]string) (*Stack, error) {
+	resume, err := ApplyOverrides(s.Resume, overrides)
+	vals, verr := resumeValues(resume)
+	if verr != nil {
+		return nil, verr
+	}
+	sources := make(map[string]string, len(s.Sources)+len(overrides))
+	for k, v := range s.Sources {
+		sources[k] = v
+	}
+	for k := range overrides {
+		p := yamlPath(k)
+		for src := range sources {
+			// an override replaces everything below the path it sets
+			if src == p || strings.HasPrefix(src, p+".") {
+				delete(sources, src)
+			}
+		}
+		recordLeaves(lookupValue(vals, p), p, OverridesLayer, sources)
+	}
+	return &Stack{Resume: resume, Sources: sources, values: vals}, err
+}
+
+// Value returns the merged value at a yaml path such as "experience.0.company".
+func (s *Stack) Value(path string) any {
+	return lookupValue(s.values, path)
+}
+
+// Paths returns the yaml paths of all leaf values in sorted order.
+func (s *Stack) Paths() []string {
+	paths := make([]string, 0, len(s.Sources))
+	for p := range s.Sources {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// IsLayerFile reports whether path is a values file that can be layered on
+// the base resume, one ListValuesFiles lists, rather than a complete resume
+// such as the base values file, a profile or a file outside ValuesDir.
+func IsLayerFile(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil || !IsValuesFile(path) {
+		return false
+	}
+	root, err := filepath.Abs(ValuesDir())
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	first, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	return !isResumeFile(rel) && first != ProfilesDirName
+}
+
+// ListValuesFiles returns the values files (YAML, JSON or TOML) under ValuesDir that can be layered
+// on top of the base resume, as slash separated paths relative to ValuesDir.
+// The base resume file and profiles are not included.
+func ListValuesFiles() ([]string, error) {
+	root := ValuesDir()
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == root {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != root {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			// profiles are alternative bases, not layers
+			if path == ProfilesDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !IsValuesFile(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if isResumeFile(rel) {
+			return nil
+		}
+		out = append(out, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func newStack(vals map[string]any, sources map[string]string) (*Stack, error) {
+	b, err := yaml.Marshal(vals)
+	if err != nil {
+		return nil, err
+	}
+	var resume Resume
+	if err := yaml.Unmarshal(b, &resume); err != nil {
+		return nil, err
+	}
+	return &Stack{Resume: resume, Sources: sources, values: vals}, nil
+}
+
+// resumeValues converts a resume into generic yaml values with empty values removed.
+func resumeValues(r Resume) (map[string]any, error) {
+	b, err := yaml.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	var vals map[string]any
+	if err := yaml.Unmarshal(b, &vals); err != nil {
+		return nil, err
+	}
+	pruned, _ := pruneEmpty(vals).(map[string]any)
+	if pruned == nil {
+		pruned = map[string]any{}
+	}
+	return pruned, nil
+}
+
+// pruneEmpty drops empty strings, nils and empty collections from maps.
+// List entries are kept so indexes still line up between layers.
+func pruneEmpty(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			val = pruneEmpty(val)
+			if isEmptyValue(val) {
+				delete(t, k)
+				continue
+			}
+			t[k] = val
+		}
+		return t
+	case []any:
+		for i := range t {
+			t[i] = pruneEmpty(t[i])
+		}
+		return t
+	}
+	return v
+}
+
+func isEmptyValue(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		return len(t) == 0
+	}
+	return false
+}
+
+// mergeValues merges overlay into base (see Stack) and records the layer of
+// every leaf taken from overlay in sources.
+func mergeValues(base, overlay any, path, layer string, sources map[string]string) any {
+	switch ov := overlay.(type) {
+	case map[string]any:
+		bm, ok := base.(map[string]any)
+		if !ok {
+			break
+		}
+		for k, v := range ov {
+			bm[k] = mergeValues(bm[k], v, joinPath(path, k), layer, sources)
+		}
+		return bm
+	case []any:
+		bl, ok := base.([]any)
+		if !ok {
+			break
+		}
+		if isMapList(ov) || isMapList(bl) {
+			for i, v := range ov {
+				p := joinPath(path, strconv.Itoa(i))
+				if i < len(bl) {
+					bl[i] = mergeValues(bl[i], v, p, layer, sources)
+				} else {
+					bl = append(bl, v)
+					recordLeaves(v, p, layer, sources)
+				}
+			}
+			return bl
+		}
+		for _, v := range ov {
+			if containsValue(bl, v) {
+				continue
+			}
+			bl = append(bl, v)
+			recordLeaves(v, joinPath(path, strconv.Itoa(len(bl)-1)), layer, sources)
+		}
+		return bl
+	}
+	for src := range sources {
+		if strings.HasPrefix(src, path+".") {
+			delete(sources, src)
+		}
+	}
+	recordLeaves(overlay, path, layer, sources)
+	return overlay
+}
+
+func recordLeaves(v any, path, layer string, sources map[string]string) {
+	switch t := v.(type) {
+	case nil:
+	case map[string]any:
+		for k, val := range t {
+			recordLeaves(val, joinPath(path, k), layer, sources)
+		}
+	case []any:
+		for i, val := range t {
+			recordLeaves(val, joinPath(path, strconv.Itoa(i)), layer, sources)
+		}
+	default:
+		sources[path] = layer
+	}
+}
+
+func lookupValue(v any, path string) any {
+	if path == "" {
+		return v
+	}
+	for _, seg := range strings.Split(path, ".") {
+		switch t := v.(type) {
+		case map[string]any:
+			v = t[seg]
+		case []any:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil
+			}
+			v = t[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+func isMapList(l []any) bool {
+	for _, v := range l {
+		if _, ok := v.(map[string]any); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(l []any, v any) bool {
+	for _, item := range l {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, seg string) string {
+	if path == "" {
+		return seg
+	}
+	return path + "." + seg
+}
+
+// yamlPath converts an override path using Go field names (Experience.0.Company)
+// into the yaml path used by Stack.Sources (experience.0.company). Paths that
+// are not Resume fields map into the custom section.
+func yamlPath(path string) string {
+	segs := strings.Split(strings.TrimSpace(path), ".")
+	t := reflect.TypeOf(Resume{})
+	if _, ok := structField(t, segs[0]); !ok {
+		return joinPath("custom", path)
+	}
+	out := make([]string, 0, len(segs))
+	for _, seg := range segs {
+		if t == nil {
+			out = append(out, seg)
+			continue
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			sf, ok := structField(t, seg)
+			if !ok {
+				out = append(out, seg)
+				t = nil
+				continue
+			}
+			tag, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+			if tag == "" {
+				tag = strings.ToLower(sf.Name)
+			}
+			out = append(out, tag)
+			t = sf.Type
+		case reflect.Slice, reflect.Map:
+			out = append(out, seg)
+			t = t.Elem()
+		default:
+			out = append(out, seg)
+			t = nil
+		}
+	}
+	return strings.Join(out, ".")
+}
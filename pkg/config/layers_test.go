@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeValues(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMergeLayers(t *testing.T) {
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatalf("SetMainDir: %v", err)
+	}
+	dir := ValuesDir()
+	base := writeValues(t, dir, DefaultValuesFile, `name: Jane
+email: jane@example.com
+skills: [Go, SQL]
+experience:
+  - company: Go Corp
+    position: Engineer
+    responsibilities: [Wrote code]
+custom:
+  Signature: Jane
+`)
+	overlay := writeValues(t, dir, "companies/acme.yml", `company_to_apply_to: ACME
+skills: [Kubernetes, Go]
+experience:
+  - position: Senior Engineer
+    responsibilities: [Led migrations]
+  - company: Test Inc.
+custom:
+  HiringManager: Ada
+`)
+	var layers []Layer
+	for _, p := range []string{base, overlay} {
+		l, err := LoadLayer(p)
+		if err != nil {
+			t.Fatalf("LoadLayer(%s): %v", p, err)
+		}
+		layers = append(layers, l)
+	}
+	st, err := MergeLayers(layers...)
+	if err != nil {
+		t.Fatalf("MergeLayers: %v", err)
+	}
+	r := st.Resume
+	if r.Name != "Jane" || r.CompanyToApplyTo != "ACME" {
+		t.Fatalf("unexpected scalars: %+v", r)
+	}
+	if !reflect.DeepEqual(r.Skills, []string{"Go", "SQL", "Kubernetes"}) {
+		t.Fatalf("unexpected skills: %v", r.Skills)
+	}
+	if len(r.Experience) != 2 || r.Experience[0].Company != "Go Corp" || r.Experience[0].Position != "Senior Engineer" {
+		t.Fatalf("unexpected experience: %+v", r.Experience)
+	}
+	if !reflect.DeepEqual(r.Experience[0].Responsibilities, []string{"Wrote code", "Led migrations"}) {
+		t.Fatalf("unexpected responsibilities: %v", r.Experience[0].Responsibilities)
+	}
+	if r.Custom["Signature"] != "Jane" || r.Custom["HiringManager"] != "Ada" {
+		t.Fatalf("unexpected custom: %v", r.Custom)
+	}
+
+	wantSources := map[string]string{
+		"name":                  DefaultValuesFile,
+		"company_to_apply_to":   "companies/acme.yml",
+		"skills.0":              DefaultValuesFile,
+		"skills.2":              "companies/acme.yml",
+		"experience.0.company":  DefaultValuesFile,
+		"experience.0.position": "companies/acme.yml",
+		"experience.1.company":  "companies/acme.yml",
+		"custom.HiringManager":  "companies/acme.yml",
+	}
+	for p, want := range wantSources {
+		if got := st.Sources[p]; got != want {
+			t.Fatalf("Sources[%q] = %q; want %q", p, got, want)
+		}
+	}
+
+	over, err := st.WithOverrides(map[string]string{"Experience.0.Company": "Globex", "HiringManager": "Grace"})
+	if err != nil {
+		t.Fatalf("WithOverrides: %v", err)
+	}
+	if over.Resume.Experience[0].Company != "Globex" || over.Value("custom.HiringManager") != "Grace" {
+		t.Fatalf("overrides not applied: %+v", over.Resume)
+	}
+	if over.Sources["experience.0.company"] != OverridesLayer || over.Sources["custom.HiringManager"] != OverridesLayer {
+		t.Fatalf("overrides not attributed: %v", over.Sources)
+	}
+	if st.Resume.Experience[0].Company != "Go Corp" {
+		t.Fatalf("WithOverrides modified the original stack")
+	}
+}
+
+func TestListValuesFiles(t *testing.T) {
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatalf("SetMainDir: %v", err)
+	}
+	if files, err := ListValuesFiles(); err != nil || len(files) != 0 {
+		t.Fatalf("expected no files before values dir exists, got %v (%v)", files, err)
+	}
+	dir := ValuesDir()
+	writeValues(t, dir, DefaultValuesFile, "name: Jane\n")
+	writeValues(t, dir, "roles/sre.yaml", "role_to_apply_to: SRE\n")
+	writeValues(t, dir, "acme.yml", "company_to_apply_to: ACME\n")
+	writeValues(t, dir, "notes.txt", "ignored")
+	files, err := ListValuesFiles()
+	if err != nil {
+		t.Fatalf("ListValuesFiles: %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{"acme.yml", "roles/sre.yaml"}) {
+		t.Fatalf("unexpected files: %v", files)
+	}
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// linkedInColumns lists, per CSV file of a LinkedIn data export, the columns
+// ImportLinkedIn maps. Other non-empty columns are reported as unmapped.
+var linkedInColumns = map[string][]string{
+	"Profile.csv":   {"First Name", "Last Name", "Headline", "Summary"},
+	"Positions.csv": {"Company Name", "Title", "Description", "Started On", "Finished On"},
+	"Education.csv": {"School Name", "Degree Name", "Start Date", "End Date"},
+	"Skills.csv":    {"Name"},
+	"Projects.csv":  {"Title", "Description", "Url"},
+}
+
+// ImportLinkedInFile reads a LinkedIn "Get a copy of your data" zip; see ImportLinkedIn.
+func ImportLinkedInFile(filename string) (Resume, []string, error) {
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return Resume{}, nil, err
+	}
+	defer zr.Close()
+	r, unmapped, err := importLinkedIn(&zr.Reader)
+	if err != nil {
+		return Resume{}, nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return r, unmapped, nil
+}
+
+// ImportLinkedIn builds a Resume from the contents of a LinkedIn data export
+// zip: Positions.csv, Education.csv, Skills.csv, Projects.csv and, when
+// present, Profile.csv for the name, headline and summary (the latter two as
+// the custom variables Label and Summary). Files may sit in any folder of the
+// archive; missing ones are skipped. It also returns the non-empty columns
+// that were not imported, e.g. "Positions.csv: Location".
+func ImportLinkedIn(data []byte) (Resume, []string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return Resume{}, nil, err
+	}
+	return importLinkedIn(zr)
+}
+
+func importLinkedIn(zr *zip.Reader) (Resume, []string, error) {
+	tables := map[string][]map[string]string{}
+	var unmapped []string
+	for _, f := range zr.File {
+		name := path.Base(f.Name)
+		cols, ok := linkedInColumns[name]
+		if !ok || f.FileInfo().IsDir() {
+			continue
+		}
+		rows, extra, err := readLinkedInCSV(f, cols)
+		if err != nil {
+			return Resume{}, nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		tables[name] = rows
+		for _, c := range extra {
+			unmapped = append(unmapped, name+": "+c)
+		}
+	}
+	if len(tables) == 0 {
+		return Resume{}, nil, errors.New("no LinkedIn export files found (expected Positions.csv, Education.csv, Skills.csv or Projects.csv)")
+	}
+
+	var r Resume
+	if rows := tables["Profile.csv"]; len(rows) > 0 {
+		p := rows[0]
+		r.Name = strings.Join(nonEmpty(p["First Name"], p["Last Name"]), " ")
+		for k, col := range map[string]string{"Label": "Headline", "Summary": "Summary"} {
+			if v := p[col]; v != "" {
+				if r.Custom == nil {
+					r.Custom = map[string]any{}
+				}
+				r.Custom[k] = v
+			}
+		}
+	}
+	for _, row := range tables["Positions.csv"] {
+		r.Experience = append(r.Experience, Experience{
+			Company:          row["Company Name"],
+			Position:         row["Title"],
+			StartDate:        NewDate(row["Started On"]),
+			EndDate:          NewDate(row["Finished On"]),
+			Responsibilities: SplitLines(row["Description"]),
+		})
+	}
+	for _, row := range tables["Education.csv"] {
+		r.Education = append(r.Education, Education{
+			Institution: row["School Name"],
+			Degree:      row["Degree Name"],
+			StartDate:   NewDate(row["Start Date"]),
+			EndDate:     NewDate(row["End Date"]),
+		})
+	}
+	for _, row := range tables["Skills.csv"] {
+		if s := row["Name"]; s != "" {
+			r.Skills = append(r.Skills, s)
+		}
+	}
+	for _, row := range tables["Projects.csv"] {
+		r.Projects = append(r.Projects, Project{Name: row["Title"], Description: row["Description"], URL: row["Url"]})
+	}
+
+	sort.Strings(unmapped)
+	return r, unmapped, nil
+}
+
+// readLinkedInCSV reads a CSV file into rows keyed by header. It returns the
+// headers outside cols that hold a value in any row.
+func readLinkedInCSV(f *zip.File, cols []string) ([]map[string]string, []string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, nil, err
+	}
+	cr := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(b, []byte("\ufeff"))))
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, nil
+	}
+
+	header := records[0]
+	known := map[string]bool{}
+	for _, c := range cols {
+		known[c] = true
+	}
+	extra := map[string]bool{}
+	var rows []map[string]string
+	for _, rec := range records[1:] {
+		row := map[string]string{}
+		for i, v := range rec {
+			if i >= len(header) {
+				break
+			}
+			h := strings.TrimSpace(header[i])
+			v = strings.TrimSpace(v)
+			row[h] = v
+			if v != "" && !known[h] {
+				extra[h] = true
+			}
+		}
+		rows = append(rows, row)
+	}
+	var out []string
+	for h := range extra {
+		out = append(out, h)
+	}
+	sort.Strings(out)
+	return rows, out, nil
+}
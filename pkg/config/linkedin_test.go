@@ -0,0 +1,87 @@
+package config
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// linkedInZip builds an export archive from file names and CSV contents.
+func linkedInZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportLinkedIn(t *testing.T) {
+	data := linkedInZip(t, map[string]string{
+		"Basic_LinkedInDataExport/Profile.csv": "First Name,Last Name,Headline,Summary,Industry\n" +
+			"Jane,Doe,Backend Engineer,,Software\n",
+		"Basic_LinkedInDataExport/Positions.csv": "\ufeffCompany Name,Title,Description,Location,Started On,Finished On\n" +
+			"ACME,Engineer,\"Built the ledger\nCut latency 40%\",Remote,Jun 2019,\n" +
+			"Initech,Intern,,,Jan 2018,Aug 2018\n",
+		"Education.csv": "School Name,Start Date,End Date,Notes,Degree Name,Activities\n" +
+			"State University,2014,2018,,BSc Computer Science,\n",
+		"Skills.csv":      "Name\nGo\nSQL\n",
+		"Projects.csv":    "Title,Description,Url,Started On,Finished On\ncovlet,Cover letters,https://example.com,,\n",
+		"Connections.csv": "First Name,Last Name\nSam,Smith\n",
+	})
+
+	r, unmapped, err := ImportLinkedIn(data)
+	if err != nil {
+		t.Fatalf("ImportLinkedIn: %v", err)
+	}
+	if r.Name != "Jane Doe" || r.Custom["Label"] != "Backend Engineer" {
+		t.Fatalf("unexpected profile: %+v", r)
+	}
+	if _, ok := r.Custom["Summary"]; ok {
+		t.Fatalf("expected an empty summary to be skipped")
+	}
+	if len(r.Experience) != 2 {
+		t.Fatalf("expected two positions, got %d", len(r.Experience))
+	}
+	ex := r.Experience[0]
+	if ex.Company != "ACME" || ex.StartDate.Format("iso") != "2019-06" || !ex.EndDate.IsZero() {
+		t.Fatalf("unexpected position: %+v", ex)
+	}
+	if !reflect.DeepEqual(ex.Responsibilities, []string{"Built the ledger", "Cut latency 40%"}) {
+		t.Fatalf("Responsibilities = %v", ex.Responsibilities)
+	}
+	if ed := r.Education[0]; ed.Institution != "State University" || ed.Degree != "BSc Computer Science" || ed.EndDate.String() != "2018" {
+		t.Fatalf("unexpected education: %+v", ed)
+	}
+	if !reflect.DeepEqual(r.Skills, []string{"Go", "SQL"}) {
+		t.Fatalf("Skills = %v", r.Skills)
+	}
+	if p := r.Projects[0]; p.Name != "covlet" || p.URL != "https://example.com" {
+		t.Fatalf("unexpected project: %+v", p)
+	}
+	want := []string{"Positions.csv: Location", "Profile.csv: Industry"}
+	if !reflect.DeepEqual(unmapped, want) {
+		t.Fatalf("unmapped = %v, want %v", unmapped, want)
+	}
+}
+
+func TestImportLinkedIn_NoExportFiles(t *testing.T) {
+	data := linkedInZip(t, map[string]string{"Connections.csv": "First Name\nSam\n"})
+	if _, _, err := ImportLinkedIn(data); err == nil {
+		t.Fatalf("expected an error for an archive without export files")
+	}
+	if _, _, err := ImportLinkedIn([]byte("not a zip")); err == nil {
+		t.Fatalf("expected an error for a non-zip file")
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Conflict is a value that an incoming resume would change in an existing one.
+type Conflict struct {
+	// Path names the value, e.g. "Name" or "Experience[ACME / Engineer].EndDate".
+	Path     string
+	Current  string
+	Incoming string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.Path, c.Current, c.Incoming)
+}
+
+// MergeConflicts lists the values MergeResumes would ask about.
+func MergeConflicts(dst, src Resume) []Conflict {
+	var out []Conflict
+	MergeResumes(dst, src, func(c Conflict) bool {
+		out = append(out, c)
+		return false
+	})
+	return out
+}
+
+// MergeResumes merges src, e.g. an imported resume, into a copy of dst.
+// Values missing from dst are filled in from src. Entries are matched by
+// company and position (experience), institution (education) or name
+// (projects); unmatched entries from src are appended, and responsibilities
+// and skills are combined without duplicates. When both sides hold different
+// values, takeIncoming decides: it returns true to use the value from src.
+// A nil takeIncoming keeps dst.
+func MergeResumes(dst, src Resume, takeIncoming func(Conflict) bool) Resume {
+	if takeIncoming == nil {
+		takeIncoming = func(Conflict) bool { return false }
+	}
+	m := merger{take: takeIncoming}
+	out := dst.Clone()
+	src = src.Clone()
+
+	m.fields(reflect.ValueOf(&out).Elem(), reflect.ValueOf(src), "")
+	out.Experience = mergeEntries(m, out.Experience, src.Experience, "Experience", func(e Experience) string {
+		return entryKey(e.Company, e.Position)
+	})
+	out.Education = mergeEntries(m, out.Education, src.Education, "Education", func(e Education) string {
+		return entryKey(e.Institution)
+	})
+	out.Projects = mergeEntries(m, out.Projects, src.Projects, "Projects", func(p Project) string {
+		return entryKey(p.Name)
+	})
+	for k, v := range src.Custom {
+		cur, ok := out.Custom[k]
+		switch {
+		case !ok || isEmptyValue(cur):
+			if out.Custom == nil {
+				out.Custom = map[string]any{}
+			}
+			out.Custom[k] = v
+		case !strings.EqualFold(fmt.Sprint(cur), fmt.Sprint(v)):
+			if m.take(Conflict{Path: "Custom." + k, Current: fmt.Sprint(cur), Incoming: fmt.Sprint(v)}) {
+				out.Custom[k] = v
+			}
+		}
+	}
+	return out
+}
+
+type merger struct {
+	take func(Conflict) bool
+}
+
+// fields merges the string and Date fields of the struct src into dst;
+// string lists are combined. Values differing only in case or surrounding
+// space are not conflicts. Other fields are merged by the caller.
+func (m merger) fields(dst, src reflect.Value, prefix string) {
+	for i := 0; i < dst.NumField(); i++ {
+		name := dst.Type().Field(i).Name
+		d, s := dst.Field(i), src.Field(i)
+		switch {
+		case d.Type() == reflect.TypeOf([]string(nil)):
+			d.Set(reflect.ValueOf(unionStrings(d.Interface().([]string), s.Interface().([]string))))
+		case d.Kind() != reflect.String && d.Type() != reflect.TypeOf(Date{}):
+			continue
+		case isBlank(s):
+		case isBlank(d):
+			d.Set(s)
+		default:
+			cur, in := fmt.Sprint(d.Interface()), fmt.Sprint(s.Interface())
+			if !strings.EqualFold(strings.TrimSpace(cur), strings.TrimSpace(in)) && m.take(Conflict{Path: prefix + name, Current: cur, Incoming: in}) {
+				d.Set(s)
+			}
+		}
+	}
+}
+
+// mergeEntries merges the entries of src into dst, matching them by key.
+func mergeEntries[T any](m merger, dst, src []T, section string, key func(T) string) []T {
+	for _, s := range src {
+		k := key(s)
+		matched := false
+		for i := range dst {
+			if k != "" && key(dst[i]) == k {
+				m.fields(reflect.ValueOf(&dst[i]).Elem(), reflect.ValueOf(s), fmt.Sprintf("%s[%s].", section, entryLabelOf(dst[i])))
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
+
+// entryLabelOf names an entry in conflict paths by its first non-empty fields.
+func entryLabelOf(v any) string {
+	switch e := v.(type) {
+	case Experience:
+		return strings.Join(nonEmpty(e.Company, e.Position), " / ")
+	case Education:
+		return e.Institution
+	case Project:
+		return e.Name
+	}
+	return ""
+}
+
+func entryKey(parts ...string) string {
+	for i, p := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(p))
+	}
+	if strings.Join(parts, "") == "" {
+		return ""
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// unionStrings appends the values of b missing from a, ignoring case.
+func unionStrings(a, b []string) []string {
+	seen := map[string]bool{}
+	for _, s := range a {
+		seen[strings.ToLower(strings.TrimSpace(s))] = true
+	}
+	for _, s := range b {
+		k := strings.ToLower(strings.TrimSpace(s))
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		a = append(a, s)
+	}
+	return a
+}
+
+// isBlank reports a zero value, a blank string or an empty date.
+func isBlank(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	if d, ok := v.Interface().(Date); ok {
+		return d.IsZero()
+	}
+	if v.Kind() == reflect.String {
+		return strings.TrimSpace(v.String()) == ""
+	}
+	return v.IsZero()
+}
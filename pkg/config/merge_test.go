@@ -0,0 +1,59 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeResumes(t *testing.T) {
+	dst := Resume{
+		Name:  "Jane Doe",
+		Email: "jane@example.com",
+		Experience: []Experience{
+			{Company: "ACME", Position: "Engineer", StartDate: NewDate("2019"), Responsibilities: []string{"Built the ledger"}},
+		},
+		Skills: []string{"Go"},
+		Custom: map[string]any{"HiringManager": "Sam"},
+	}
+	src := Resume{
+		Name:  "Jane Q. Doe",
+		Phone: "+1 555 000 0000",
+		Experience: []Experience{
+			{Company: "acme", Position: "engineer", StartDate: NewDate("Jun 2019"), Responsibilities: []string{"built the ledger", "Cut latency"}},
+			{Company: "Initech", Position: "Intern"},
+		},
+		Skills: []string{"go", "SQL"},
+		Custom: map[string]any{"HiringManager": "Alex", "Label": "SRE"},
+	}
+
+	conflicts := MergeConflicts(dst, src)
+	var paths []string
+	for _, c := range conflicts {
+		paths = append(paths, c.Path)
+	}
+	want := []string{"Name", "Experience[ACME / Engineer].StartDate", "Custom.HiringManager"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("conflicts = %v, want %v", conflicts, want)
+	}
+
+	got := MergeResumes(dst, src, func(c Conflict) bool { return c.Path == "Name" })
+	if got.Name != "Jane Q. Doe" || got.Email != "jane@example.com" || got.Phone != "+1 555 000 0000" {
+		t.Fatalf("unexpected contact fields: %+v", got)
+	}
+	if len(got.Experience) != 2 || got.Experience[1].Company != "Initech" {
+		t.Fatalf("expected the unmatched position to be appended, got %+v", got.Experience)
+	}
+	ex := got.Experience[0]
+	if ex.StartDate.String() != "2019" || !reflect.DeepEqual(ex.Responsibilities, []string{"Built the ledger", "Cut latency"}) {
+		t.Fatalf("unexpected merged position: %+v", ex)
+	}
+	if !reflect.DeepEqual(got.Skills, []string{"Go", "SQL"}) {
+		t.Fatalf("Skills = %v", got.Skills)
+	}
+	if got.Custom["HiringManager"] != "Sam" || got.Custom["Label"] != "SRE" {
+		t.Fatalf("Custom = %v", got.Custom)
+	}
+	if dst.Name != "Jane Doe" || len(dst.Experience[0].Responsibilities) != 1 {
+		t.Fatalf("dst was modified")
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the version of the values file schema this build reads
+// and writes. Files without a version key are version 0.
+const SchemaVersion = 1
+
+// VersionKey is the top-level key holding the schema version of a values file.
+const VersionKey = "version"
+
+// Migration upgrades a values document from schema version From to From+1.
+// Apply edits the top-level mapping in place and returns a description of
+// each change it made; it is not called on included files.
+type Migration struct {
+	From    int
+	Summary string
+	Apply   func(root *yaml.Node) ([]string, error)
+}
+
+// migrations holds one step per schema version, sorted by From.
+var migrations []Migration
+
+// logf reports migrations; replaced in tests.
+var logf = log.Printf
+
+// registerMigration adds a migration step. Steps must form an unbroken chain
+// from version 0 to SchemaVersion.
+func registerMigration(m Migration) {
+	migrations = append(migrations, m)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].From < migrations[j].From })
+}
+
+func init() {
+	registerMigration(Migration{
+		From:    0,
+		Summary: "turn comma-separated skills and single responsibilities into lists",
+		Apply:   migrateV0Lists,
+	})
+}
+
+// documentVersion returns the schema version of a values document.
+func documentVersion(root *yaml.Node) (int, error) {
+	n := mappingValue(root, VersionKey)
+	if n == nil || isEmptyNode(n) {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(n.Value)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("line %d: invalid %s %q", n.Line, VersionKey, n.Value)
+	}
+	if v > SchemaVersion {
+		return 0, fmt.Errorf("line %d: values file is schema version %d, newer than this covlet supports (%d); please upgrade covlet", n.Line, v, SchemaVersion)
+	}
+	return v, nil
+}
+
+// migrateDocument upgrades doc in place to SchemaVersion, one migration at a
+// time, and sets its version key. It returns the version doc had and the
+// changes the migrations made; when there are none, only the version key was
+// added and the file need not be rewritten before the next save.
+func migrateDocument(doc *yaml.Node) (from int, changes []string, err error) {
+	root := documentRoot(doc)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return SchemaVersion, nil, nil
+	}
+	from, err = documentVersion(root)
+	if err != nil || from == SchemaVersion {
+		return from, nil, err
+	}
+	for v := from; v < SchemaVersion; v++ {
+		m, ok := migrationFrom(v)
+		if !ok {
+			return from, nil, fmt.Errorf("no migration from schema version %d", v)
+		}
+		out, err := m.Apply(root)
+		if err != nil {
+			return from, nil, fmt.Errorf("migrate from version %d: %w", v, err)
+		}
+		for _, c := range out {
+			changes = append(changes, fmt.Sprintf("v%d→v%d: %s", v, v+1, c))
+		}
+	}
+	setVersion(root, SchemaVersion)
+	return from, changes, nil
+}
+
+func migrationFrom(v int) (Migration, bool) {
+	for _, m := range migrations {
+		if m.From == v {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+// setVersion sets the version key of a mapping, adding it first if missing.
+func setVersion(root *yaml.Node, v int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(v)}
+	if n := mappingValue(root, VersionKey); n != nil {
+		*n = *value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: VersionKey}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// migrateFile writes a values file upgraded by migrateDocument back to disk.
+// The original file is first copied, as is, to <name>.v<from>.bak unless that
+// backup already exists, and the changes are logged.
+func migrateFile(filename string, doc *yaml.Node, from int, changes []string, encrypted bool) error {
+	backup := fmt.Sprintf("%s.v%d.bak", filename, from)
+	if _, err := os.Stat(backup); os.IsNotExist(err) {
+		original, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(backup, original, 0o600); err != nil {
+			return fmt.Errorf("back up %s before migration: %w", filename, err)
+		}
+	}
+	b, err := encodeDocument(doc, FormatOf(filename))
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		if b, err = encrypt(b); err != nil {
+			return err
+		}
+	}
+	if err := writeFileAtomic(filename, b, 0o644); err != nil {
+		return err
+	}
+	logf("migrated %s from schema version %d to %d (backup: %s)", filename, from, SchemaVersion, backup)
+	for _, c := range changes {
+		logf("  %s", c)
+	}
+	return nil
+}
+
+// migrateV0Lists fixes hand-written values that never loaded: `skills: Go, SQL`
+// becomes a list of skills and a single responsibility string a one-item list.
+func migrateV0Lists(root *yaml.Node) ([]string, error) {
+	var changes []string
+	if n := mappingValue(root, "skills"); n != nil && n.ShortTag() == "!!str" && n.Value != "" {
+		var items []*yaml.Node
+		for _, s := range strings.Split(n.Value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				items = append(items, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s})
+			}
+		}
+		*n = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: items, Line: n.Line, Column: n.Column}
+		changes = append(changes, fmt.Sprintf("skills: split into a list of %d", len(items)))
+	}
+	if exp := mappingValue(root, "experience"); exp != nil && exp.Kind == yaml.SequenceNode {
+		for i, entry := range exp.Content {
+			n := mappingValue(entry, "responsibilities")
+			if n == nil || n.ShortTag() != "!!str" || n.Value == "" {
+				continue
+			}
+			item := *n
+			*n = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{&item}, Line: n.Line, Column: n.Column}
+			changes = append(changes, fmt.Sprintf("experience.%d.responsibilities: made a list", i))
+		}
+	}
+	return changes, nil
+}
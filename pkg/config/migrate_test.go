@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// captureMigrationLog collects migration log lines for one test.
+func captureMigrationLog(t *testing.T) *[]string {
+	t.Helper()
+	var lines []string
+	old := logf
+	logf = func(format string, args ...any) { lines = append(lines, fmt.Sprintf(format, args...)) }
+	t.Cleanup(func() { logf = old })
+	return &lines
+}
+
+func TestMigrations_FormAChain(t *testing.T) {
+	for v := 0; v < SchemaVersion; v++ {
+		if _, ok := migrationFrom(v); !ok {
+			t.Errorf("no migration from schema version %d", v)
+		}
+	}
+}
+
+func TestMigrateV0Lists(t *testing.T) {
+	var doc yaml.Node
+	src := "name: Jane\nskills: Go, SQL ,, Docker\nexperience:\n  - company: ACME\n    responsibilities: Built the ledger\n  - company: Initech\n    responsibilities: [Fixed printers]\n"
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := migrateV0Lists(doc.Content[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %q", changes)
+	}
+	var r Resume
+	if err := doc.Decode(&r); err != nil {
+		t.Fatalf("migrated document does not decode: %v", err)
+	}
+	if strings.Join(r.Skills, "|") != "Go|SQL|Docker" {
+		t.Fatalf("Skills = %q", r.Skills)
+	}
+	if got := r.Experience[0].Responsibilities; len(got) != 1 || got[0] != "Built the ledger" {
+		t.Fatalf("Responsibilities = %q", got)
+	}
+	if got := r.Experience[1].Responsibilities; len(got) != 1 || got[0] != "Fixed printers" {
+		t.Fatalf("lists must be left alone, got %q", got)
+	}
+
+	// running it again changes nothing
+	if again, _ := migrateV0Lists(doc.Content[0]); len(again) != 0 {
+		t.Fatalf("expected no changes on a migrated document, got %q", again)
+	}
+}
+
+func TestLoadConfig_MigratesOldFile(t *testing.T) {
+	logged := captureMigrationLog(t)
+	path := filepath.Join(t.TempDir(), "resume.yml")
+	original := "# my resume\n\nname: Jane Doe\nskills: Go, SQL\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Resume.Version != SchemaVersion || len(cfg.Resume.Skills) != 2 {
+		t.Fatalf("unexpected resume: %+v", cfg.Resume)
+	}
+	if _, ok := cfg.Resume.Data()["Version"]; ok {
+		t.Fatal("the schema version must not be a template variable")
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil || string(backup) != original {
+		t.Fatalf("expected the original in the backup, got %q, %v", backup, err)
+	}
+	b, _ := os.ReadFile(path)
+	out := string(b)
+	for _, want := range []string{"# my resume", "version: 1", "- Go\n", "- SQL\n"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in migrated file:\n%s", want, out)
+		}
+	}
+	if len(*logged) == 0 || !strings.Contains((*logged)[0], "from schema version 0 to 1") || !strings.Contains(strings.Join(*logged, "\n"), "skills") {
+		t.Fatalf("expected the migration to be logged, got %q", *logged)
+	}
+
+	// a current file is not migrated again
+	*logged = nil
+	if _, err := LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if len(*logged) != 0 {
+		t.Fatalf("unexpected migration log: %q", *logged)
+	}
+}
+
+func TestLoadConfig_UnversionedFileWithoutChanges(t *testing.T) {
+	logged := captureMigrationLog(t)
+	path := filepath.Join(t.TempDir(), "resume.yml")
+	if err := os.WriteFile(path, []byte("name: Jane Doe\nskills: [Go]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".v0.bak"); !os.IsNotExist(err) || len(*logged) != 0 {
+		t.Fatalf("expected the file to be left alone until saved, got %v, %q", err, *logged)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(b), "version: 1\n") {
+		t.Fatalf("expected the version first in the saved file:\n%s", b)
+	}
+}
+
+func TestLoadConfig_NewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resume.yml")
+	data := fmt.Sprintf("name: Jane Doe\nversion: %d\n", SchemaVersion+1)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "newer than this covlet supports") {
+		t.Fatalf("expected a newer-version error, got %v", err)
+	}
+	diags := Validate([]byte(data))
+	if len(diags) != 1 || diags[0].Line != 2 || diags[0].Severity != SeverityError {
+		t.Fatalf("expected one error at line 2, got %v", diags)
+	}
+}
+
+func TestValidate_OldVersion(t *testing.T) {
+	diags := Validate([]byte("name: Jane Doe\nemail: jane@example.com\nskills: Go, SQL\n"))
+	if len(diags) != 1 || diags[0].Severity != SeverityWarning || !strings.Contains(diags[0].Message, "upgraded to 1") {
+		t.Fatalf("expected a single upgrade warning, got %v", diags)
+	}
+}
@@ -0,0 +1,306 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownPath is reported for override paths that do not resolve to a Resume field.
+var ErrUnknownPath = errors.New("unknown path")
+
+// ApplyOverrides returns a copy of in with every override applied. Keys are
+// dotted paths such as "Name", "Experience.0.Company" or "Skills.2"; segments
+// match Go field names or yaml tags case-insensitively, and numeric segments
+// index into lists (the index one past the end appends a new entry). List
+// fields take comma or newline separated input. A single segment that is not
+// a Resume field sets a custom variable, e.g. "HiringManager" is stored as
+// Custom["HiringManager"]; nested custom variables take the Custom prefix, as
+// in "Custom.Posting.URL".
+//
+// Overrides are applied in path order, comparing list indexes as numbers so
+// that "Experience.2.Company" comes before "Experience.10.Company". All paths
+// that could not be applied are reported together; the others still take
+// effect.
+func ApplyOverrides(in Resume, overrides map[string]string) (Resume, error) {
+	out := in.Clone()
+	if len(overrides) == 0 {
+		return out, nil
+	}
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return pathLess(keys[i], keys[j]) })
+
+	var errs []error
+	for _, k := range keys {
+		if err := out.SetPath(k, overrides[k]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+// pathLess orders dotted paths segment by segment, comparing numeric segments
+// as integers and others as text.
+func pathLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		if aErr == nil && bErr == nil && an != bn {
+			return an < bn
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
+
+// SetPath assigns value to the field addressed by a dotted path. See ApplyOverrides
+// for the path syntax.
+func (r *Resume) SetPath(path, value string) error {
+	segs := strings.Split(strings.TrimSpace(path), ".")
+	if len(segs) == 0 || segs[0] == "" {
+		return fmt.Errorf("%q: %w", path, ErrUnknownPath)
+	}
+	rv := reflect.ValueOf(r).Elem()
+	if _, ok := fieldByName(rv, segs[0]); !ok && len(segs) == 1 {
+		// not a typed field: treat it as a free-form custom variable; deeper
+		// custom paths need the Custom prefix so typos in typed paths such as
+		// "Experiance.0.Company" are still reported
+		rv = rv.FieldByName("Custom")
+	}
+	if err := setPath(rv, segs, value); err != nil {
+		return fmt.Errorf("%q: %w", path, err)
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the resume so callers can modify lists without
+// touching the original.
+func (r Resume) Clone() Resume {
+	return deepCopy(reflect.ValueOf(r)).Interface().(Resume)
+}
+
+func setPath(v reflect.Value, segs []string, value string) error {
+	if len(segs) == 0 {
+		return setValue(v, value)
+	}
+	seg := segs[0]
+	switch v.Kind() {
+	case reflect.Struct:
+		f, ok := fieldByName(v, seg)
+		if !ok {
+			return fmt.Errorf("%w: no field %q in %s", ErrUnknownPath, seg, v.Type().Name())
+		}
+		return setPath(f, segs[1:], value)
+	case reflect.Slice:
+		i, err := strconv.Atoi(seg)
+		if err != nil || i < 0 {
+			return fmt.Errorf("%w: %q is not a list index", ErrUnknownPath, seg)
+		}
+		if i > v.Len() {
+			return fmt.Errorf("%w: index %d out of range (len %d)", ErrUnknownPath, i, v.Len())
+		}
+		if i == v.Len() {
+			v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+		}
+		return setPath(v.Index(i), segs[1:], value)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		key := reflect.ValueOf(seg)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if cur := v.MapIndex(key); cur.IsValid() {
+			elem.Set(cur)
+		}
+		if elem.Kind() == reflect.Interface {
+			// free-form values: strings at the leaves, nested maps in between
+			if len(segs) == 1 {
+				elem.Set(reflect.ValueOf(value))
+				v.SetMapIndex(key, elem)
+				return nil
+			}
+			nested, ok := elem.Interface().(map[string]any)
+			if !ok {
+				if !elem.IsNil() {
+					return fmt.Errorf("%w: %q is not a map", ErrUnknownPath, seg)
+				}
+				nested = map[string]any{}
+			}
+			if err := setPath(reflect.ValueOf(nested), segs[1:], value); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, reflect.ValueOf(nested))
+			return nil
+		}
+		if err := setPath(elem, segs[1:], value); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	}
+	return fmt.Errorf("%w: cannot descend into %s with %q", ErrUnknownPath, v.Type(), seg)
+}
+
+// fieldByName finds a struct field by Go name or yaml tag, ignoring case.
+// Inline fields are not addressable by name.
+func fieldByName(v reflect.Value, name string) (reflect.Value, bool) {
+	sf, ok := structField(v.Type(), name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return v.FieldByIndex(sf.Index), true
+}
+
+// structField is the type-level lookup behind fieldByName.
+func structField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || strings.Contains(sf.Tag.Get("yaml"), ",inline") {
+			continue
+		}
+		tag, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if strings.EqualFold(sf.Name, name) || (tag != "" && strings.EqualFold(tag, name)) {
+			return sf, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// setValue converts the text input to the type of v.
+func setValue(v reflect.Value, value string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		v.SetFloat(f)
+		return nil
+	case reflect.Slice:
+		items := SplitList(value)
+		out := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(out.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+		return nil
+	}
+	return fmt.Errorf("cannot assign text to %s; use a path to one of its fields", v.Type())
+}
+
+// deepCopy copies slices, maps and pointers reachable from v.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return out
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(deepCopy(v.Elem()))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(deepCopy(v.Elem()))
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return out
+	}
+	return v
+}
+
+// SplitList splits comma or newline separated input into trimmed, non-empty items.
+func SplitList(input string) []string {
+	return splitTrim(strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == '\n' }))
+}
+
+// SplitLines splits newline separated input into trimmed, non-empty items.
+// Unlike SplitList, commas are kept, which suits sentences.
+func SplitLines(input string) []string {
+	return splitTrim(strings.Split(input, "\n"))
+}
+
+func splitTrim(fields []string) []string {
+	var out []string
+	for _, f := range fields {
+		if s := strings.TrimSpace(f); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// RemoveAt returns list without the element at index i; an index out of
+// range leaves it unchanged. list itself is not modified.
+func RemoveAt[T any](list []T, i int) []T {
+	if i < 0 || i >= len(list) {
+		return list
+	}
+	return append(list[:i:i], list[i+1:]...)
+}
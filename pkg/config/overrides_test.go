@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func sampleResume() Resume {
+	return Resume{
+		Name:   "John Doe",
+		Skills: []string{"Go", "Python", "Docker"},
+		Experience: []Experience{
+			{Company: "Go Corp", Position: "Engineer", Responsibilities: []string{"Wrote code."}},
+		},
+		Education: []Education{{Institution: "University of Go"}},
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	cases := []struct {
+		name      string
+		overrides map[string]string
+		check     func(r Resume) bool
+	}{
+		{"top level by field name", map[string]string{"Name": "Jane"}, func(r Resume) bool { return r.Name == "Jane" }},
+		{"top level by yaml tag", map[string]string{"company_to_apply_to": "ACME"}, func(r Resume) bool { return r.CompanyToApplyTo == "ACME" }},
+		{"case insensitive", map[string]string{"roletoapplyto": "SRE"}, func(r Resume) bool { return r.RoleToApplyTo == "SRE" }},
+		{"nested struct in list", map[string]string{"Experience.0.Company": "ACME"}, func(r Resume) bool { return r.Experience[0].Company == "ACME" }},
+		{"typed date", map[string]string{"Experience.0.StartDate": "June 2022"}, func(r Resume) bool {
+			return r.Experience[0].StartDate.String() == "June 2022" && r.Experience[0].StartDate.Valid()
+		}},
+		{"list element", map[string]string{"Skills.2": "Rust"}, func(r Resume) bool {
+			return reflect.DeepEqual(r.Skills, []string{"Go", "Python", "Rust"})
+		}},
+		{"append to list", map[string]string{"Skills.3": "SQL"}, func(r Resume) bool { return len(r.Skills) == 4 && r.Skills[3] == "SQL" }},
+		{"append struct entry", map[string]string{"Projects.0.Name": "Covlet"}, func(r Resume) bool {
+			return len(r.Projects) == 1 && r.Projects[0].Name == "Covlet"
+		}},
+		{"unknown top level becomes custom", map[string]string{"HiringManager": "Ada"}, func(r Resume) bool {
+			return r.Custom["HiringManager"] == "Ada"
+		}},
+		{"nested custom path", map[string]string{"Custom.Posting.URL": "https://example.com"}, func(r Resume) bool {
+			m, ok := r.Custom["Posting"].(map[string]any)
+			return ok && m["URL"] == "https://example.com"
+		}},
+		{"list from commas", map[string]string{"Skills": "Go, Kubernetes"}, func(r Resume) bool {
+			return reflect.DeepEqual(r.Skills, []string{"Go", "Kubernetes"})
+		}},
+		{"list from lines", map[string]string{"Experience.0.Responsibilities": "Built things\nOn call"}, func(r Resume) bool {
+			return reflect.DeepEqual(r.Experience[0].Responsibilities, []string{"Built things", "On call"})
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			in := sampleResume()
+			out, err := ApplyOverrides(in, c.overrides)
+			if err != nil {
+				t.Fatalf("ApplyOverrides: %v", err)
+			}
+			if !c.check(out) {
+				t.Fatalf("unexpected result: %+v", out)
+			}
+			if !reflect.DeepEqual(in, sampleResume()) {
+				t.Fatalf("input was modified: %+v", in)
+			}
+		})
+	}
+}
+
+func TestApplyOverrides_Errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		path    string
+		unknown bool
+	}{
+		{"unknown field", "Nickname.First", true},
+		{"misspelled list", "Experiance.0.Company", true},
+		{"misspelled list sibling", "Educaton.0.School", true},
+		{"descend into custom string", "Custom.Manager.Name", true},
+		{"unknown nested field", "Experience.0.Compnay", true},
+		{"index out of range", "Experience.5.Company", true},
+		{"non numeric index", "Skills.first", true},
+		{"descend into string", "Name.First", true},
+		{"assign to struct list", "Experience", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			in := sampleResume()
+			in.Custom = map[string]any{"Manager": "Ada"}
+			out, err := ApplyOverrides(in, map[string]string{c.path: "x", "Name": "Jane"})
+			if err == nil {
+				t.Fatalf("expected error for %q", c.path)
+			}
+			if got := errors.Is(err, ErrUnknownPath); got != c.unknown {
+				t.Fatalf("errors.Is(ErrUnknownPath) = %v; want %v (%v)", got, c.unknown, err)
+			}
+			if out.Name != "Jane" {
+				t.Fatalf("expected valid overrides to still apply, got %q", out.Name)
+			}
+		})
+	}
+}
+
+func TestApplyOverrides_IndexOrder(t *testing.T) {
+	overrides := map[string]string{}
+	var want []string
+	for i := 0; i < 12; i++ {
+		name := fmt.Sprintf("Project %d", i)
+		overrides[fmt.Sprintf("Projects.%d.Name", i)] = name
+		want = append(want, name)
+	}
+	out, err := ApplyOverrides(Resume{}, overrides)
+	if err != nil {
+		t.Fatalf("ApplyOverrides: %v", err)
+	}
+	var got []string
+	for _, p := range out.Projects {
+		got = append(got, p.Name)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("projects = %v, want %v", got, want)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	cases := map[string][]string{
+		" Go ,, Python ":    {"Go", "Python"},
+		"Go, SQL\n\nDocker": {"Go", "SQL", "Docker"},
+		"":                  nil,
+		"single":            {"single"},
+	}
+	for in, want := range cases {
+		if got := SplitList(in); !reflect.DeepEqual(got, want) {
+			t.Fatalf("SplitList(%q) = %v; want %v", in, got, want)
+		}
+	}
+}
+
+func TestRemoveAt(t *testing.T) {
+	list := []string{"a", "b", "c"}
+	if got := RemoveAt(list, 1); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Fatalf("RemoveAt = %v; want [a c]", got)
+	}
+	if !reflect.DeepEqual(list, []string{"a", "b", "c"}) {
+		t.Fatalf("RemoveAt changed its input: %v", list)
+	}
+	if got := RemoveAt([]string{"a"}, 3); len(got) != 1 {
+		t.Fatalf("expected out of range index to be ignored, got %v", got)
+	}
+}
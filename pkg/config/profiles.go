@@ -0,0 +1,245 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ProfilesDirName is the directory inside ValuesDir holding named profiles.
+const ProfilesDirName = "profiles"
+
+// activeProfile is the profile selected for this run; empty means the
+// profile remembered in the settings file, or the default resume.
+var activeProfile string
+
+var profileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 _.-]*$`)
+
+// ProfilesDir returns the directory holding named profiles.
+func ProfilesDir() string {
+	return filepath.Join(ValuesDir(), ProfilesDirName)
+}
+
+// ProfilePath returns the values file of the named profile: the first of
+// ValuesExtensions that exists, else the .yml file a new profile is saved as.
+func ProfilePath(name string) string {
+	for _, ext := range ValuesExtensions {
+		p := filepath.Join(ProfilesDir(), name+ext)
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			return p
+		}
+	}
+	return filepath.Join(ProfilesDir(), name+".yml")
+}
+
+// ListProfiles returns the names of all profiles in sorted order.
+func ListProfiles() ([]string, error) {
+	entries, err := os.ReadDir(ProfilesDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	seen := map[string]bool{}
+	for _, e := range entries {
+		if e.IsDir() || !IsValuesFile(e.Name()) {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		if !seen[name] && checkProfileName(name) == nil {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CreateProfile writes a new profile holding r and returns its config.
+func CreateProfile(name string, r Resume) (*Config, error) {
+	if err := checkNewProfile(name); err != nil {
+		return nil, err
+	}
+	cfg := &Config{Resume: r}
+	if err := cfg.SaveConfig(ProfilePath(name)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ContactOnly keeps the contact details of r and drops everything else; a
+// new profile starts from them.
+func ContactOnly(r Resume) Resume {
+	return Resume{
+		Name:    r.Name,
+		Email:   r.Email,
+		Phone:   r.Phone,
+		Address: r.Address,
+		Website: r.Website,
+		Github:  r.Github,
+	}
+}
+
+// CloneProfile copies the values file of profile src to a new profile dst,
+// keeping its comments and format. An empty src clones the default resume,
+// found like ResolveConfigFile does but ignoring the active profile.
+func CloneProfile(src, dst string) error {
+	if err := checkNewProfile(dst); err != nil {
+		return err
+	}
+	from, err := resolveConfigFile("", false)
+	if src != "" {
+		if err := checkProfileName(src); err != nil {
+			return err
+		}
+		from, err = ProfilePath(src), nil
+	}
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(profileFile(dst, from), b, 0o644)
+}
+
+// profileFile is the file for the new profile name with the extension of
+// from, so a copied or renamed values file keeps its format.
+func profileFile(name, from string) string {
+	ext := strings.ToLower(filepath.Ext(from))
+	if !IsValuesFile(from) {
+		ext = ".yml"
+	}
+	return filepath.Join(ProfilesDir(), name+ext)
+}
+
+// RenameProfile renames a profile, following it in the settings file if it
+// is the remembered profile.
+func RenameProfile(from, to string) error {
+	if err := checkProfileName(from); err != nil {
+		return err
+	}
+	if err := checkNewProfile(to); err != nil {
+		return err
+	}
+	path := ProfilePath(from)
+	if err := os.Rename(path, profileFile(to, path)); err != nil {
+		return err
+	}
+	if activeProfile == from {
+		activeProfile = to
+	}
+	return updateRememberedProfile(from, to)
+}
+
+// DeleteProfile removes a profile. If it was active, the default resume is
+// used again.
+func DeleteProfile(name string) error {
+	if err := checkProfileName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(ProfilePath(name)); err != nil {
+		return err
+	}
+	if activeProfile == name {
+		activeProfile = ""
+	}
+	return updateRememberedProfile(name, "")
+}
+
+// UseProfile selects the profile for this run without remembering it, e.g.
+// from a --profile flag. An empty name selects the default resume.
+func UseProfile(name string) error {
+	if name != "" {
+		if err := checkProfileName(name); err != nil {
+			return err
+		}
+		if _, err := os.Stat(ProfilePath(name)); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+	activeProfile = name
+	return nil
+}
+
+// RememberProfile selects the profile and records it in the settings file so
+// it is used again on the next start in the same main directory. An empty
+// name selects the default resume.
+func RememberProfile(name string) error {
+	if err := UseProfile(name); err != nil {
+		return err
+	}
+	st, err := LoadSettings()
+	if err != nil {
+		return err
+	}
+	st.setProfile(name)
+	return st.Save()
+}
+
+// ActiveProfile returns the selected profile: the one chosen with UseProfile,
+// else the one remembered for the main directory if it still exists. Empty
+// means the default resume.
+func ActiveProfile() string {
+	if activeProfile != "" {
+		return activeProfile
+	}
+	st, err := LoadSettings()
+	if err != nil {
+		return ""
+	}
+	name := st.Profiles[GetMainDir()]
+	if name == "" || checkProfileName(name) != nil {
+		return ""
+	}
+	if _, err := os.Stat(ProfilePath(name)); err != nil {
+		return ""
+	}
+	return name
+}
+
+func updateRememberedProfile(from, to string) error {
+	st, err := LoadSettings()
+	if err != nil || st.Profiles[GetMainDir()] != from {
+		return err
+	}
+	st.setProfile(to)
+	return st.Save()
+}
+
+// setProfile remembers name for the current main directory.
+func (s *Settings) setProfile(name string) {
+	if name == "" {
+		delete(s.Profiles, GetMainDir())
+		return
+	}
+	if s.Profiles == nil {
+		s.Profiles = map[string]string{}
+	}
+	s.Profiles[GetMainDir()] = name
+}
+
+func checkProfileName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, spaces, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+func checkNewProfile(name string) error {
+	if err := checkProfileName(name); err != nil {
+		return err
+	}
+	if _, err := os.Stat(ProfilePath(name)); err == nil {
+		return fmt.Errorf("profile %q already exists: %w", name, fs.ErrExist)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfiles_Lifecycle(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(ConfigEnv, "")
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { activeProfile = "" })
+
+	if names, err := ListProfiles(); err != nil || len(names) != 0 {
+		t.Fatalf("expected no profiles, got %v (%v)", names, err)
+	}
+	if _, err := CreateProfile("backend", Resume{Name: "Jane", Skills: []string{"Go"}}); err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	if _, err := CreateProfile("backend", Resume{}); !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("expected ErrExist for a duplicate profile, got %v", err)
+	}
+	if _, err := CreateProfile("../escape", Resume{}); err == nil {
+		t.Fatalf("expected an invalid name to be rejected")
+	}
+	if err := CloneProfile("backend", "SRE"); err != nil {
+		t.Fatalf("CloneProfile: %v", err)
+	}
+	names, err := ListProfiles()
+	if err != nil || !reflect.DeepEqual(names, []string{"SRE", "backend"}) {
+		t.Fatalf("ListProfiles = %v, %v", names, err)
+	}
+
+	if err := RememberProfile("SRE"); err != nil {
+		t.Fatalf("RememberProfile: %v", err)
+	}
+	activeProfile = "" // as on a fresh start
+	if got, _ := ResolveConfigFile(""); got != ProfilePath("SRE") {
+		t.Fatalf("expected the remembered profile to resolve, got %q", got)
+	}
+	if err := RenameProfile("SRE", "management"); err != nil {
+		t.Fatalf("RenameProfile: %v", err)
+	}
+	activeProfile = ""
+	if ActiveProfile() != "management" {
+		t.Fatalf("expected the remembered profile to follow the rename, got %q", ActiveProfile())
+	}
+	cfg, err := LoadConfig(ProfilePath("management"))
+	if err != nil || cfg.Resume.Name != "Jane" {
+		t.Fatalf("expected cloned content, got %+v (%v)", cfg, err)
+	}
+
+	other := GetMainDir()
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(ProfilesDir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ProfilePath("management"), []byte("name: Other\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if ActiveProfile() != "" {
+		t.Fatalf("expected the profile remembered for another main dir to be ignored, got %q", ActiveProfile())
+	}
+	if err := SetMainDir(other); err != nil {
+		t.Fatal(err)
+	}
+	if ActiveProfile() != "management" {
+		t.Fatalf("expected the remembered profile back in its main dir, got %q", ActiveProfile())
+	}
+
+	if err := UseProfile("backend"); err != nil {
+		t.Fatalf("UseProfile: %v", err)
+	}
+	if ActiveProfile() != "backend" {
+		t.Fatalf("expected UseProfile to win over the remembered profile")
+	}
+	if err := UseProfile("missing"); err == nil {
+		t.Fatalf("expected an error for a missing profile")
+	}
+
+	if err := DeleteProfile("management"); err != nil {
+		t.Fatalf("DeleteProfile: %v", err)
+	}
+	activeProfile = ""
+	if ActiveProfile() != "" {
+		t.Fatalf("expected the default resume after deleting the remembered profile")
+	}
+	if _, err := os.Stat(ProfilePath("management")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected profile file to be removed, got %v", err)
+	}
+}
+
+func TestListValuesFiles_SkipsProfiles(t *testing.T) {
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateProfile("backend", Resume{Name: "Jane"}); err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	writeValues(t, ValuesDir(), "acme.yml", "company_to_apply_to: ACME\n")
+	files, err := ListValuesFiles()
+	if err != nil || strings.Join(files, ",") != "acme.yml" {
+		t.Fatalf("ListValuesFiles = %v, %v", files, err)
+	}
+}
+
+func TestProfiles_OtherFormats(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(ConfigEnv, "")
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { activeProfile = "" })
+	writeValues(t, ProfilesDir(), "json.json", `{"name": "Ann"}`)
+	writeValues(t, ProfilesDir(), "toml.toml", "name = \"Tom\"\n")
+	writeValues(t, ProfilesDir(), "yaml.yaml", "name: Yan\n")
+
+	names, err := ListProfiles()
+	if err != nil || !reflect.DeepEqual(names, []string{"json", "toml", "yaml"}) {
+		t.Fatalf("ListProfiles = %v, %v", names, err)
+	}
+	if err := UseProfile("toml"); err != nil {
+		t.Fatalf("UseProfile: %v", err)
+	}
+	if got, _ := ResolveConfigFile(""); got != filepath.Join(ProfilesDir(), "toml.toml") {
+		t.Fatalf("expected the TOML profile to resolve, got %q", got)
+	}
+	if err := RenameProfile("json", "renamed"); err != nil {
+		t.Fatalf("RenameProfile: %v", err)
+	}
+	cfg, err := LoadConfig(ProfilePath("renamed"))
+	if err != nil || cfg.Resume.Name != "Ann" || filepath.Ext(ProfilePath("renamed")) != ".json" {
+		t.Fatalf("expected the renamed profile to stay JSON, got %s %+v (%v)", ProfilePath("renamed"), cfg, err)
+	}
+}
+
+func TestCloneProfile_ResolvesDefaultResume(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(ConfigEnv, "")
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { activeProfile = "" })
+
+	// legacy config.yml in the main directory
+	writeValues(t, GetMainDir(), LegacyConfigFile, "name: Legacy\n")
+	if _, err := CreateProfile("other", Resume{Name: "Other"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := UseProfile("other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CloneProfile("", "fromLegacy"); err != nil {
+		t.Fatalf("CloneProfile: %v", err)
+	}
+	if cfg, err := LoadConfig(ProfilePath("fromLegacy")); err != nil || cfg.Resume.Name != "Legacy" {
+		t.Fatalf("expected the legacy resume to be cloned, got %+v (%v)", cfg, err)
+	}
+
+	// COVLET_CONFIG pointing at a JSON resume keeps its format
+	env := filepath.Join(t.TempDir(), "resume.json")
+	if err := os.WriteFile(env, []byte(`{"name": "Env"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigEnv, env)
+	if err := CloneProfile("", "fromEnv"); err != nil {
+		t.Fatalf("CloneProfile: %v", err)
+	}
+	if cfg, err := LoadConfig(ProfilePath("fromEnv")); err != nil || cfg.Resume.Name != "Env" || filepath.Ext(ProfilePath("fromEnv")) != ".json" {
+		t.Fatalf("expected the COVLET_CONFIG resume to be cloned as JSON, got %s %+v (%v)", ProfilePath("fromEnv"), cfg, err)
+	}
+}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tags for references in values files.
+const (
+	// IncludeTag replaces a value with the contents of another values file,
+	// e.g. `address: !include private/address.yml`.
+	IncludeTag = "!include"
+	// FileTag replaces a value with the text of a file, e.g.
+	// `summary: !file summary.txt`. A trailing newline is dropped.
+	FileTag = "!file"
+)
+
+// envRe matches ${NAME} and ${NAME:-default}.
+var envRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// ReferenceError is a reference that could not be resolved. File, Line and
+// Column locate the reference itself.
+type ReferenceError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// refs maps the nodes of a values document that are references to the nodes
+// they resolved to, so SaveConfig can keep a reference whose value did not change.
+type refs map[*yaml.Node]*yaml.Node
+
+// resolveReferences returns a copy of doc with references resolved:
+// ${ENV_VAR} (or ${ENV_VAR:-default}) in string values is expanded, and
+// values tagged !include or !file are replaced by the file they name.
+// Relative paths are resolved against ValuesDir. Included values files may
+// use references themselves; include cycles are an error. Nodes of included
+// files take the position of the !include so diagnostics point into filename.
+func resolveReferences(doc *yaml.Node, filename string) (*yaml.Node, refs, error) {
+	r := &resolver{refs: refs{}}
+	if abs, err := filepath.Abs(filename); err == nil {
+		r.chain = []string{abs}
+	}
+	out, err := r.resolve(doc, filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, r.refs, nil
+}
+
+type resolver struct {
+	// chain is the include path from the loaded file to the current one
+	chain []string
+	refs  refs
+}
+
+func (r *resolver) resolve(n *yaml.Node, file string) (*yaml.Node, error) {
+	if n == nil {
+		return nil, nil
+	}
+	cp := *n
+	switch {
+	case n.Tag == IncludeTag:
+		included, err := r.include(n, file)
+		if err != nil {
+			return nil, err
+		}
+		r.record(n, included)
+		return included, nil
+	case n.Tag == FileTag:
+		b, err := os.ReadFile(r.refPath(n.Value))
+		if err != nil {
+			return nil, r.errorf(n, file, "!file %s: %v", n.Value, unwrapPathError(err))
+		}
+		cp.Tag, cp.Value, cp.Style = "!!str", strings.TrimSuffix(string(b), "\n"), 0
+		if strings.Contains(cp.Value, "\n") {
+			cp.Style = yaml.LiteralStyle
+		}
+		r.record(n, &cp)
+		return &cp, nil
+	case n.Kind == yaml.ScalarNode && n.ShortTag() == "!!str" && strings.Contains(n.Value, "${"):
+		v, err := r.expandEnv(n, file)
+		if err != nil {
+			return nil, err
+		}
+		cp.Tag, cp.Value = "!!str", v
+		r.record(n, &cp)
+		return &cp, nil
+	}
+	if len(n.Content) > 0 {
+		cp.Content = make([]*yaml.Node, len(n.Content))
+		for i, c := range n.Content {
+			rc, err := r.resolve(c, file)
+			if err != nil {
+				return nil, err
+			}
+			cp.Content[i] = rc
+		}
+	}
+	return &cp, nil
+}
+
+// include loads the values file named by n and returns its resolved top-level value.
+func (r *resolver) include(n *yaml.Node, file string) (*yaml.Node, error) {
+	if n.Kind != yaml.ScalarNode || n.Value == "" {
+		return nil, r.errorf(n, file, "!include needs a file name")
+	}
+	path := r.refPath(n.Value)
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, r.errorf(n, file, "!include %s: %v", n.Value, err)
+	}
+	for i, c := range r.chain {
+		if c == abs {
+			cycle := append(append([]string(nil), r.chain[i:]...), abs)
+			for j := range cycle {
+				cycle[j] = LayerName(cycle[j])
+			}
+			return nil, r.errorf(n, file, "include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	b, _, err := readValuesFile(path)
+	if err != nil {
+		return nil, r.errorf(n, file, "!include %s: %v", n.Value, unwrapPathError(err))
+	}
+	doc, err := parseDocument(b, FormatOf(path))
+	if err != nil {
+		return nil, r.errorf(n, file, "!include %s: %v", n.Value, err)
+	}
+	root := documentRoot(doc)
+
+	r.chain = append(r.chain, abs)
+	defer func() { r.chain = r.chain[:len(r.chain)-1] }()
+	resolved, err := r.resolve(root, path)
+	if err != nil {
+		// report at the !include so the error has a position in file
+		return nil, r.errorf(n, file, "!include %s: %v", n.Value, err)
+	}
+	relocate(resolved, n.Line, n.Column)
+	return resolved, nil
+}
+
+// expandEnv expands ${NAME} and ${NAME:-default} in a string value.
+func (r *resolver) expandEnv(n *yaml.Node, file string) (string, error) {
+	var missing []string
+	out := envRe.ReplaceAllStringFunc(n.Value, func(m string) string {
+		sub := envRe.FindStringSubmatch(m)
+		hasDefault := strings.Contains(m, ":-")
+		if v, ok := os.LookupEnv(sub[1]); ok && (v != "" || !hasDefault) {
+			return v
+		}
+		if hasDefault {
+			return sub[2]
+		}
+		missing = append(missing, sub[1])
+		return m
+	})
+	if len(missing) > 0 {
+		return "", r.errorf(n, file, "environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+func (r *resolver) record(raw, resolved *yaml.Node) {
+	// only references in the loaded file itself can be kept on save
+	if len(r.chain) <= 1 {
+		r.refs[raw] = resolved
+	}
+}
+
+func (r *resolver) refPath(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(ValuesDir(), filepath.FromSlash(p))
+}
+
+func (r *resolver) errorf(n *yaml.Node, file, format string, args ...any) error {
+	return &ReferenceError{File: file, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+// relocate moves n and its descendants to the given position.
+func relocate(n *yaml.Node, line, col int) {
+	n.Line, n.Column = line, col
+	for _, c := range n.Content {
+		relocate(c, line, col)
+	}
+}
+
+func unwrapPathError(err error) error {
+	var pe *os.PathError
+	if errors.As(err, &pe) {
+		return pe.Err
+	}
+	return err
+}
+
+// sameValue reports whether two nodes hold the same data, comparing scalars
+// by their text. A missing mapping key equals an empty value, so a resolved
+// reference matches the freshly encoded Resume it decoded into.
+func sameValue(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return isEmptyNode(a) && isEmptyNode(b)
+	}
+	a, b = resolveAlias(a), resolveAlias(b)
+	if isEmptyNode(a) && isEmptyNode(b) {
+		return true
+	}
+	if a.Kind != b.Kind {
+		return false
+	}
+	switch a.Kind {
+	case yaml.ScalarNode:
+		return a.Value == b.Value
+	case yaml.SequenceNode:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := range a.Content {
+			if !sameValue(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	case yaml.MappingNode:
+		keys := map[string]bool{}
+		for _, m := range []*yaml.Node{a, b} {
+			for i := 0; i+1 < len(m.Content); i += 2 {
+				keys[m.Content[i].Value] = true
+			}
+		}
+		for k := range keys {
+			if !sameValue(mappingValue(a, k), mappingValue(b, k)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func isEmptyNode(n *yaml.Node) bool {
+	if n == nil {
+		return true
+	}
+	n = resolveAlias(n)
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Value == "" || n.ShortTag() == "!!null"
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(n.Content) == 0
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// referenceHome points the main dir at a temp dir and returns its values dir.
+func referenceHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	if err := SetMainDir(home); err != nil {
+		t.Fatal(err)
+	}
+	dir, err := EnsureValuesDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadConfig_ResolvesReferences(t *testing.T) {
+	dir := referenceHome(t)
+	t.Setenv("COVLET_TEST_PHONE", "+1 555 000 0000")
+	t.Setenv("COVLET_TEST_EMPTY", "")
+	writeValues(t, dir, "private/summary.txt", "Builds reliable services.\n")
+	writeValues(t, dir, "private/contact.yml", "email: jane@example.com\nphone: ${COVLET_TEST_PHONE}\n")
+	writeValues(t, dir, "jobs.yml", "- company: ACME\n  position: Engineer\n  start_date: 2018\n")
+	path := writeValues(t, dir, "resume.yml", `name: Jane Doe
+phone: "${COVLET_TEST_PHONE}"
+address: ${COVLET_TEST_UNSET:-Springfield}
+website: https://${COVLET_TEST_EMPTY}example.com
+experience: !include jobs.yml
+custom:
+  Summary: !file private/summary.txt
+  Contact: !include private/contact.yml
+`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	r := cfg.Resume
+	if r.Phone != "+1 555 000 0000" || r.Address != "Springfield" || r.Website != "https://example.com" {
+		t.Fatalf("unexpected expansion: %+v", r)
+	}
+	if len(r.Experience) != 1 || r.Experience[0].Company != "ACME" || r.Experience[0].StartDate.String() != "2018" {
+		t.Fatalf("unexpected included experience: %+v", r.Experience)
+	}
+	if r.Custom["Summary"] != "Builds reliable services." {
+		t.Fatalf("Summary = %q", r.Custom["Summary"])
+	}
+	contact, _ := r.Custom["Contact"].(map[string]any)
+	if contact["phone"] != "+1 555 000 0000" {
+		t.Fatalf("expected references inside included files to resolve, got %v", contact)
+	}
+}
+
+func TestSaveConfig_KeepsUnchangedReferences(t *testing.T) {
+	dir := referenceHome(t)
+	t.Setenv("COVLET_TEST_PHONE", "+1 555 000 0000")
+	writeValues(t, dir, "jobs.yml", "- company: ACME\n  position: Engineer\n")
+	path := writeValues(t, dir, "resume.yml", "name: Jane Doe\nphone: ${COVLET_TEST_PHONE}\naddress: ${COVLET_TEST_PHONE}\nexperience: !include jobs.yml\n")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Resume.Name = "Jane Q. Doe"
+	cfg.Resume.Address = "1 Main St"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	b, _ := os.ReadFile(path)
+	out := string(b)
+	for _, want := range []string{"name: Jane Q. Doe", "phone: ${COVLET_TEST_PHONE}", "address: 1 Main St", "experience: !include jobs.yml"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in saved file:\n%s", want, out)
+		}
+	}
+
+	// a reference replaced by a literal stays replaced on later saves
+	cfg.Resume.Address = "+1 555 000 0000"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(path)
+	if !strings.Contains(string(b), `address: +1 555 000 0000`) {
+		t.Fatalf("expected the literal address to be saved:\n%s", b)
+	}
+}
+
+func TestLoadConfig_ReferenceErrors(t *testing.T) {
+	dir := referenceHome(t)
+	writeValues(t, dir, "a.yml", "custom:\n  b: !include b.yml\n")
+	writeValues(t, dir, "b.yml", "nested: !include a.yml\n")
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		want    string
+	}{
+		{"unset env", "name: Jane\n\nphone: ${COVLET_TEST_UNSET}\n", 3, "COVLET_TEST_UNSET is not set"},
+		{"missing file", "name: Jane\nsummary: !file nope.txt\n", 2, "nope.txt"},
+		{"missing include", "name: !include nope.yml\n", 1, "nope.yml"},
+		{"cycle", "name: Jane\nextra: !include a.yml\n", 2, "include cycle: a.yml -> b.yml -> a.yml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeValues(t, dir, "resume.yml", tt.content)
+			_, err := LoadConfig(path)
+			var re *ReferenceError
+			if !errors.As(err, &re) {
+				t.Fatalf("expected a ReferenceError, got %v", err)
+			}
+			if re.File != path || re.Line != tt.line || !strings.Contains(re.Message, tt.want) {
+				t.Fatalf("got %v, want %s:%d: ...%s", err, path, tt.line, tt.want)
+			}
+
+			diags, err := ValidateFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(diags) != 1 || diags[0].Line != tt.line {
+				t.Fatalf("expected one diagnostic at line %d, got %v", tt.line, diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// HomeEnv names the environment variable that selects the app main directory.
+	HomeEnv = "COVLET_HOME"
+	// ConfigEnv names the environment variable that points at a resume values file.
+	ConfigEnv = "COVLET_CONFIG"
+	// LegacyConfigFile is the values file name used before values/ existed.
+	LegacyConfigFile = "config.yml"
+)
+
+// Settings are app preferences persisted between runs. They live in the XDG
+// config dir rather than the main dir, since they record where the main dir is.
+type Settings struct {
+	// MainDir is the main directory last chosen with "Open Folder…".
+	MainDir string `yaml:"main_dir,omitempty"`
+	// Profiles maps each main directory to the profile last chosen in it;
+	// none means the default resume.
+	Profiles map[string]string `yaml:"profiles,omitempty"`
+}
+
+// ConfigHomeDir returns Covlet's directory in the user config dir,
+// e.g. $XDG_CONFIG_HOME/covlet or ~/.config/covlet on Linux.
+func ConfigHomeDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "covlet"), nil
+}
+
+// SettingsPath returns the path of the settings file.
+func SettingsPath() (string, error) {
+	dir, err := ConfigHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings.yml"), nil
+}
+
+// LoadSettings reads the settings file. A missing file yields empty settings.
+func LoadSettings() (*Settings, error) {
+	path, err := SettingsPath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Settings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var st Settings
+	if err := yaml.Unmarshal(b, &st); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &st, nil
+}
+
+// Save writes the settings file atomically.
+func (s *Settings) Save() error {
+	path, err := SettingsPath()
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, b, 0o644)
+}
+
+// RememberMainDir sets the main directory and records it in the settings file
+// so it is used again on the next start.
+func RememberMainDir(dir string) error {
+	if err := SetMainDir(dir); err != nil {
+		return err
+	}
+	st, err := LoadSettings()
+	if err != nil {
+		return err
+	}
+	st.MainDir = GetMainDir()
+	return st.Save()
+}
+
+// ResolveConfigFile picks the resume values file, in order of precedence:
+//  1. explicit, e.g. from a --config flag
+//  2. the COVLET_CONFIG environment variable
+//  3. the active profile (see ActiveProfile)
+//  4. the main dir: values/resume.yml (or .yaml, .json, .toml), then the
+//     legacy config.yml
+//  5. config.yml in ConfigHomeDir
+//
+// An explicit or environment path is returned as is, even if it does not
+// exist, so the error names the file the user asked for. When nothing is found
+// it returns ResumePath and an error wrapping fs.ErrNotExist, so callers can
+// run the setup wizard to create it.
+func ResolveConfigFile(explicit string) (string, error) {
+	return resolveConfigFile(explicit, true)
+}
+
+// resolveConfigFile is ResolveConfigFile, skipping step 3 unless profiles is set.
+func resolveConfigFile(explicit string, profiles bool) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	if env := os.Getenv(ConfigEnv); env != "" {
+		return env, nil
+	}
+	if p := ActiveProfile(); profiles && p != "" {
+		return ProfilePath(p), nil
+	}
+	var candidates []string
+	for _, f := range resumeFiles {
+		candidates = append(candidates, filepath.Join(ValuesDir(), f))
+	}
+	candidates = append(candidates, filepath.Join(GetMainDir(), LegacyConfigFile))
+	if dir, err := ConfigHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, LegacyConfigFile))
+	}
+	for _, c := range candidates {
+		if fi, err := os.Stat(c); err == nil && !fi.IsDir() {
+			return c, nil
+		}
+	}
+	return ResumePath(), fmt.Errorf("no resume values file found (looked in %s): %w", ResumePath(), fs.ErrNotExist)
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRememberMainDir_PersistsAcrossResolve(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(HomeEnv, "")
+	chosen := t.TempDir()
+	if err := RememberMainDir(chosen); err != nil {
+		t.Fatalf("RememberMainDir: %v", err)
+	}
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if err := ResolveMainDir(""); err != nil {
+		t.Fatalf("ResolveMainDir: %v", err)
+	}
+	if GetMainDir() != chosen {
+		t.Fatalf("expected remembered main dir %q, got %q", chosen, GetMainDir())
+	}
+
+	// the environment wins over the settings file, an explicit dir over both
+	env := t.TempDir()
+	t.Setenv(HomeEnv, env)
+	if err := ResolveMainDir(""); err != nil || GetMainDir() != env {
+		t.Fatalf("expected env main dir %q, got %q (%v)", env, GetMainDir(), err)
+	}
+	explicit := t.TempDir()
+	if err := ResolveMainDir(explicit); err != nil || GetMainDir() != explicit {
+		t.Fatalf("expected explicit main dir %q, got %q (%v)", explicit, GetMainDir(), err)
+	}
+	if err := ResolveMainDir(filepath.Join(explicit, "missing")); err == nil {
+		t.Fatalf("expected an error for a missing explicit dir")
+	}
+}
+
+func TestResolveConfigFile_Precedence(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv(ConfigEnv, "")
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ResolveConfigFile(""); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist without any values file, got %v", err)
+	}
+
+	steps := []struct {
+		create string
+		want   string
+	}{
+		{filepath.Join(xdg, "covlet", LegacyConfigFile), filepath.Join(xdg, "covlet", LegacyConfigFile)},
+		{filepath.Join(GetMainDir(), LegacyConfigFile), filepath.Join(GetMainDir(), LegacyConfigFile)},
+		{ResumePath(), ResumePath()},
+	}
+	for _, s := range steps {
+		if err := os.MkdirAll(filepath.Dir(s.create), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(s.create, []byte("name: Jane\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ResolveConfigFile("")
+		if err != nil || got != s.want {
+			t.Fatalf("ResolveConfigFile = %q, %v; want %q", got, err, s.want)
+		}
+	}
+
+	t.Setenv(ConfigEnv, "/from/env.yml")
+	if got, _ := ResolveConfigFile(""); got != "/from/env.yml" {
+		t.Fatalf("expected env path, got %q", got)
+	}
+	if got, _ := ResolveConfigFile("flag.yml"); got != "flag.yml" {
+		t.Fatalf("expected explicit path, got %q", got)
+	}
+}
@@ -0,0 +1,358 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// parseTOML decodes a TOML values file into a YAML document node. Keys keep
+// their document order, and key and value nodes carry the line and column of
+// the key in data so diagnostics point into the TOML file.
+func parseTOML(data []byte) (*yaml.Node, error) {
+	var m map[string]any
+	md, err := toml.Decode(string(data), &m)
+	if err != nil {
+		return nil, err
+	}
+	b := &tomlBuilder{order: map[string]int{}, pos: tomlKeyPositions(string(data), md)}
+	for i, k := range md.Keys() {
+		if _, ok := b.order[tomlKey(k)]; !ok {
+			b.order[tomlKey(k)] = i
+		}
+	}
+	root := b.node(m, "", tomlPos{1, 1})
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}, Line: 1, Column: 1}, nil
+}
+
+type tomlPos struct{ line, col int }
+
+// tomlBuilder converts decoded TOML values to nodes. order is the index of
+// each key path in the document and pos the positions of its occurrences,
+// consumed in document order.
+type tomlBuilder struct {
+	order map[string]int
+	pos   map[string][]tomlPos
+}
+
+func (b *tomlBuilder) node(v any, path string, at tomlPos) *yaml.Node {
+	scalar := func(tag, value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value, Line: at.line, Column: at.col}
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: at.line, Column: at.col}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.SliceStable(keys, func(i, j int) bool {
+			oi, oki := b.order[joinTOMLKey(path, keys[i])]
+			oj, okj := b.order[joinTOMLKey(path, keys[j])]
+			if oki != okj {
+				return oki
+			}
+			if oi != oj {
+				return oi < oj
+			}
+			return keys[i] < keys[j]
+		})
+		for _, k := range keys {
+			p := joinTOMLKey(path, k)
+			kat := at
+			if q := b.pos[p]; len(q) > 0 {
+				kat = q[0]
+				// each table of an array of tables has its own [[header]]
+				if _, tables := v[k].([]map[string]any); !tables {
+					b.pos[p] = q[1:]
+				}
+			}
+			key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k, Line: kat.line, Column: kat.col}
+			n.Content = append(n.Content, key, b.node(v[k], p, kat))
+		}
+		return n
+	case []map[string]any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: at.line, Column: at.col}
+		for _, item := range v {
+			iat := at
+			if q := b.pos[path]; len(q) > 0 {
+				iat, b.pos[path] = q[0], q[1:]
+			}
+			n.Content = append(n.Content, b.node(item, path, iat))
+		}
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: at.line, Column: at.col}
+		for _, item := range v {
+			n.Content = append(n.Content, b.node(item, path, at))
+		}
+		return n
+	case string:
+		return scalar("!!str", v)
+	case bool:
+		return scalar("!!bool", strconv.FormatBool(v))
+	case int64:
+		return scalar("!!int", strconv.FormatInt(v, 10))
+	case float64:
+		return scalar("!!float", tomlFloat(v))
+	case time.Time:
+		// dates stay text so Date keeps them as written
+		switch v.Location().String() {
+		case "date-local":
+			return scalar("!!str", v.Format("2006-01-02"))
+		case "datetime-local":
+			return scalar("!!str", v.Format("2006-01-02T15:04:05"))
+		case "time-local":
+			return scalar("!!str", v.Format("15:04:05"))
+		}
+		return scalar("!!str", v.Format(time.RFC3339))
+	}
+	return scalar("!!str", fmt.Sprint(v))
+}
+
+func tomlKey(k toml.Key) string { return strings.Join(k, "\x00") }
+
+func joinTOMLKey(path, k string) string {
+	if path == "" {
+		return k
+	}
+	return path + "\x00" + k
+}
+
+// tomlKeyPositions finds where each key of md is defined in src, walking the
+// keys in document order. Tables defined by a [header] or [[header]] map to
+// the header line; other keys to their "key =" assignment. Keys that cannot
+// be found get the position of the previous key.
+func tomlKeyPositions(src string, md toml.MetaData) map[string][]tomlPos {
+	lines := strings.Split(src, "\n")
+	out := map[string][]tomlPos{}
+	line, col := 0, 0
+	for _, k := range md.Keys() {
+		at := tomlPos{line + 1, col + 1}
+		found := false
+		if t := md.Type(k...); t == "Hash" || t == "ArrayHash" {
+			want := normalizeTOMLKey(strings.Join(k, "."))
+			for i := line; i < len(lines); i++ {
+				h := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(h, "[") {
+					continue
+				}
+				if c := strings.Index(h, "#"); c >= 0 {
+					h = h[:c]
+				}
+				if normalizeTOMLKey(strings.Trim(strings.TrimSpace(h), "[]")) == want {
+					at, found = tomlPos{i + 1, strings.Index(lines[i], "[") + 1}, true
+					line, col = i+1, 0
+					break
+				}
+			}
+		}
+		if !found {
+			re := tomlAssignRe(k[len(k)-1])
+			for i, start := line, col; i < len(lines); i, start = i+1, 0 {
+				if start > len(lines[i]) {
+					continue
+				}
+				if loc := re.FindStringSubmatchIndex(lines[i][start:]); loc != nil {
+					at = tomlPos{i + 1, start + loc[2] + 1}
+					line, col = i, start+loc[3]
+					break
+				}
+			}
+		}
+		out[tomlKey(k)] = append(out[tomlKey(k)], at)
+	}
+	return out
+}
+
+// tomlAssignRe matches an assignment to key name, bare or quoted, possibly
+// as part of a dotted key or inside an inline table. Group 1 is the key.
+func tomlAssignRe(name string) *regexp.Regexp {
+	q := regexp.QuoteMeta(name)
+	return regexp.MustCompile(`(?:^|[\s{,.])(` + q + `|"` + q + `"|'` + q + `')\s*[=.]`)
+}
+
+func normalizeTOMLKey(s string) string {
+	return strings.NewReplacer(" ", "", "\t", "", `"`, "", "'", "").Replace(s)
+}
+
+// tomlDiagnostic converts a TOML parse error into a diagnostic with its position.
+func tomlDiagnostic(err error) Diagnostic {
+	d := Diagnostic{Severity: SeverityError, Line: 1, Column: 1, Message: strings.TrimPrefix(err.Error(), "toml: ")}
+	var pe toml.ParseError
+	if errors.As(err, &pe) {
+		d.Line, d.Column, d.Message = pe.Position.Line, pe.Position.Col, pe.Message
+	}
+	return d
+}
+
+// encodeTOML renders a document node as TOML, keeping key order. Within each
+// table plain values come before sub-tables, as TOML requires; lists of
+// mappings become arrays of tables and null values are left out.
+func encodeTOML(doc *yaml.Node) ([]byte, error) {
+	root := documentRoot(doc)
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("TOML values files need a mapping at the top level")
+	}
+	var buf bytes.Buffer
+	if err := writeTOMLTable(&buf, root, ""); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeTOMLTable(buf *bytes.Buffer, n *yaml.Node, path string) error {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], resolveAlias(n.Content[i+1])
+		if isTOMLNull(v) || v.Kind == yaml.MappingNode || isTOMLTableArray(v) {
+			continue
+		}
+		s, err := tomlInline(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", joinPath(path, k.Value), err)
+		}
+		fmt.Fprintf(buf, "%s = %s\n", tomlKeyString(k.Value), s)
+	}
+	header := func(open, close, name string) {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(open + name + close + "\n")
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], resolveAlias(n.Content[i+1])
+		name := tomlKeyString(k.Value)
+		if path != "" {
+			name = path + "." + name
+		}
+		switch {
+		case v.Kind == yaml.MappingNode:
+			header("[", "]", name)
+			if err := writeTOMLTable(buf, v, name); err != nil {
+				return err
+			}
+		case isTOMLTableArray(v):
+			for _, item := range v.Content {
+				header("[[", "]]", name)
+				if err := writeTOMLTable(buf, resolveAlias(item), name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// tomlInline renders a value for the right-hand side of an assignment.
+func tomlInline(n *yaml.Node) (string, error) {
+	n = resolveAlias(n)
+	switch n.Kind {
+	case yaml.SequenceNode:
+		var items []string
+		for _, item := range n.Content {
+			if isTOMLNull(item) {
+				continue
+			}
+			s, err := tomlInline(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case yaml.MappingNode:
+		var items []string
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if isTOMLNull(n.Content[i+1]) {
+				continue
+			}
+			s, err := tomlInline(n.Content[i+1])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, tomlKeyString(n.Content[i].Value)+" = "+s)
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!bool":
+			var b bool
+			if err := n.Decode(&b); err != nil {
+				return "", err
+			}
+			return strconv.FormatBool(b), nil
+		case "!!int":
+			var i int64
+			if err := n.Decode(&i); err != nil {
+				return "", err
+			}
+			return strconv.FormatInt(i, 10), nil
+		case "!!float":
+			var f float64
+			if err := n.Decode(&f); err != nil {
+				return "", err
+			}
+			return tomlFloat(f), nil
+		}
+		return jsonQuote(n.Value), nil
+	}
+	return "", fmt.Errorf("cannot write %v node as TOML", n.Kind)
+}
+
+func tomlFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+var bareTOMLKeyRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKeyString(k string) string {
+	if bareTOMLKeyRe.MatchString(k) {
+		return k
+	}
+	return jsonQuote(k)
+}
+
+func isTOMLNull(n *yaml.Node) bool {
+	n = resolveAlias(n)
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null"
+}
+
+func isTOMLTableArray(n *yaml.Node) bool {
+	if n.Kind != yaml.SequenceNode || len(n.Content) == 0 {
+		return false
+	}
+	for _, item := range n.Content {
+		if resolveAlias(item).Kind != yaml.MappingNode {
+			return false
+		}
+	}
+	return true
+}
+
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
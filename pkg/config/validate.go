@@ -0,0 +1,413 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity classifies a Diagnostic.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Diagnostic is a single problem found in a values file.
+type Diagnostic struct {
+	Severity Severity
+	// Path is the yaml path of the offending value, e.g. "experience.0.start_date".
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	loc := fmt.Sprintf("%d:%d", d.Line, d.Column)
+	if d.Path == "" {
+		return fmt.Sprintf("%s: %s: %s", loc, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", loc, d.Severity, d.Path, d.Message)
+}
+
+// HasErrors reports whether any diagnostic is an error rather than a warning.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFile reads and validates a values file, decrypting it if needed;
+// see Validate.
+func ValidateFile(filename string) ([]Diagnostic, error) {
+	b, _, err := readValuesFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return validateData(b, FormatOf(filename), filename, false), nil
+}
+
+// ValidateLayerFile is ValidateFile for a values layer (see IsLayerFile), a
+// partial file merged over the base resume. Required fields are not
+// checked, as the base or another layer supplies them; types, unknown keys
+// and formats are.
+func ValidateLayerFile(filename string) ([]Diagnostic, error) {
+	b, _, err := readValuesFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return validateData(b, FormatOf(filename), filename, true), nil
+}
+
+// Validate checks a resume values document and returns its diagnostics sorted
+// by position. It reports YAML syntax and type errors, unknown keys, missing required fields, malformed email, URL
+// and phone values, unparseable dates and end dates before start dates.
+// Unknown top-level keys are custom variables and only flagged when they look
+// like a typo of a known key.
+func Validate(data []byte) []Diagnostic {
+	return ValidateFormat(data, FormatYAML)
+}
+
+// ValidateFormat is Validate for a values document in format f; positions
+// point into data whatever the format.
+func ValidateFormat(data []byte, f Format) []Diagnostic {
+	return validateData(data, f, "", false)
+}
+
+// validateData validates data read from filename, which may be empty. References
+// are resolved first; one that cannot be resolved is the only diagnostic. A
+// layer skips the required field checks.
+func validateData(data []byte, f Format, filename string, layer bool) []Diagnostic {
+	raw, err := parseDocument(data, f)
+	if err != nil {
+		if f == FormatTOML {
+			return []Diagnostic{tomlDiagnostic(err)}
+		}
+		return []Diagnostic{syntaxDiagnostic(err)}
+	}
+	// check the document as LoadConfig would see it after migrating
+	var migrated []Diagnostic
+	if from, changes, err := migrateDocument(raw); err != nil {
+		d := Diagnostic{Severity: SeverityError, Path: VersionKey, Line: 1, Column: 1, Message: err.Error()}
+		if n := mappingValue(documentRoot(raw), VersionKey); n != nil {
+			d.Line, d.Column = n.Line, n.Column
+			d.Message = strings.TrimPrefix(d.Message, fmt.Sprintf("line %d: ", n.Line))
+		}
+		return []Diagnostic{d}
+	} else if len(changes) > 0 {
+		migrated = append(migrated, Diagnostic{Severity: SeverityWarning, Path: VersionKey, Line: 1, Column: 1,
+			Message: fmt.Sprintf("values file is schema version %d; it is upgraded to %d (%s) when loaded", from, SchemaVersion, strings.Join(changes, "; "))})
+	}
+	doc, _, err := resolveReferences(raw, filename)
+	if err != nil {
+		d := Diagnostic{Severity: SeverityError, Line: 1, Column: 1, Message: err.Error()}
+		var re *ReferenceError
+		if errors.As(err, &re) {
+			d.Line, d.Column, d.Message = re.Line, re.Column, re.Message
+		}
+		return []Diagnostic{d}
+	}
+	v := &validator{diags: migrated, layer: layer}
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		v.add(SeverityError, nil, "", "values file is empty")
+		return v.diags
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		v.add(SeverityError, root, "", "expected a mapping of resume fields at the top level")
+		return v.diags
+	}
+
+	v.typeErrors(doc)
+	v.unknownKeys(root, reflect.TypeOf(Resume{}), "", true)
+	v.required(root, "", "name", "email")
+	if n := mappingValue(root, "email"); n != nil && n.Value != "" {
+		if a, err := mail.ParseAddress(n.Value); err != nil || a.Address != n.Value {
+			v.add(SeverityError, n, "email", "invalid email address %q", n.Value)
+		}
+	}
+	if n := mappingValue(root, "phone"); n != nil && n.Value != "" && !validPhone(n.Value) {
+		v.add(SeverityWarning, n, "phone", "phone number %q looks malformed", n.Value)
+	}
+	for _, key := range []string{"website", "github"} {
+		v.url(mappingValue(root, key), key)
+	}
+	v.entries(root, "education", func(item *yaml.Node, path string) {
+		v.required(item, path, "institution")
+		v.dateRange(item, path)
+	})
+	v.entries(root, "experience", func(item *yaml.Node, path string) {
+		v.required(item, path, "company", "position")
+		v.dateRange(item, path)
+	})
+	v.entries(root, "projects", func(item *yaml.Node, path string) {
+		v.required(item, path, "name")
+		v.url(mappingValue(item, "url"), joinPath(path, "url"))
+	})
+
+	sort.SliceStable(v.diags, func(i, j int) bool {
+		a, b := v.diags[i], v.diags[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return v.diags
+}
+
+type validator struct {
+	diags []Diagnostic
+	// layer validates a partial file, see ValidateLayerFile
+	layer bool
+}
+
+func (v *validator) add(sev Severity, n *yaml.Node, path, format string, args ...any) {
+	d := Diagnostic{Severity: sev, Path: path, Line: 1, Column: 1, Message: fmt.Sprintf(format, args...)}
+	if n != nil {
+		d.Line, d.Column = n.Line, n.Column
+	}
+	v.diags = append(v.diags, d)
+}
+
+var yamlLineRe = regexp.MustCompile(`line (\d+): (.*)`)
+
+// syntaxDiagnostic turns a yaml parse error into a diagnostic with its line.
+func syntaxDiagnostic(err error) Diagnostic {
+	d := Diagnostic{Severity: SeverityError, Line: 1, Column: 1, Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		d.Line, _ = strconv.Atoi(m[1])
+		d.Message = m[2]
+	}
+	return d
+}
+
+// typeErrors decodes the document to catch values of the wrong type.
+// Unknown keys are reported by unknownKeys, which knows their column and path.
+func (v *validator) typeErrors(doc *yaml.Node) {
+	var r Resume
+	err := doc.Decode(&r)
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return
+	}
+	for _, msg := range te.Errors {
+		if strings.Contains(msg, "not found in type") {
+			continue
+		}
+		d := Diagnostic{Severity: SeverityError, Line: 1, Column: 1, Message: msg}
+		if m := yamlLineRe.FindStringSubmatch(msg); m != nil {
+			d.Line, _ = strconv.Atoi(m[1])
+			d.Message = m[2]
+		}
+		v.diags = append(v.diags, d)
+	}
+}
+
+// unknownKeys flags mapping keys that do not match a yaml tag of t, recursing
+// into nested structs and lists of structs.
+func (v *validator) unknownKeys(n *yaml.Node, t reflect.Type, path string, topLevel bool) {
+	if n.Kind != yaml.MappingNode || t.Kind() != reflect.Struct {
+		return
+	}
+	known := yamlFields(t)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		ft, ok := known[key.Value]
+		if !ok {
+			hint := ""
+			if s := closestKey(key.Value, known); s != "" {
+				hint = fmt.Sprintf("; did you mean %q?", s)
+			}
+			if topLevel {
+				if hint != "" {
+					v.add(SeverityWarning, key, joinPath(path, key.Value), "unknown key %q%s (kept as a custom variable)", key.Value, hint)
+				}
+				continue
+			}
+			v.add(SeverityError, key, joinPath(path, key.Value), "unknown key %q%s", key.Value, hint)
+			continue
+		}
+		switch {
+		case ft.Kind() == reflect.Struct:
+			v.unknownKeys(val, ft, joinPath(path, key.Value), false)
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct && val.Kind == yaml.SequenceNode:
+			for j, item := range val.Content {
+				v.unknownKeys(item, ft.Elem(), joinPath(path, fmt.Sprintf("%s.%d", key.Value, j)), false)
+			}
+		}
+	}
+}
+
+// required flags keys of a mapping that are missing or empty.
+func (v *validator) required(n *yaml.Node, path string, keys ...string) {
+	if v.layer {
+		return
+	}
+	for _, k := range keys {
+		val := mappingValue(n, k)
+		switch {
+		case val == nil:
+			v.add(SeverityError, n, joinPath(path, k), "%s is required", k)
+		case strings.TrimSpace(val.Value) == "" && val.Kind == yaml.ScalarNode:
+			v.add(SeverityError, val, joinPath(path, k), "%s must not be empty", k)
+		}
+	}
+}
+
+// url flags values that do not look like a web address; the scheme is optional.
+func (v *validator) url(n *yaml.Node, path string) {
+	if n == nil || n.Kind != yaml.ScalarNode || n.Value == "" {
+		return
+	}
+	raw := n.Value
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || strings.ContainsAny(n.Value, " \t") || !strings.Contains(u.Hostname(), ".") ||
+		(u.Scheme != "http" && u.Scheme != "https") {
+		v.add(SeverityError, n, path, "invalid URL %q", n.Value)
+	}
+}
+
+// dateRange checks start_date and end_date of an entry.
+func (v *validator) dateRange(n *yaml.Node, path string) {
+	var start, end time.Time
+	var endPresent bool
+	if sn := mappingValue(n, "start_date"); sn != nil && sn.Value != "" {
+		t, present, err := parseFlexibleDate(sn.Value)
+		switch {
+		case err != nil:
+			v.add(SeverityError, sn, joinPath(path, "start_date"), "%v", err)
+		case present:
+			v.add(SeverityError, sn, joinPath(path, "start_date"), "start date cannot be %q", sn.Value)
+		default:
+			start = t
+		}
+	}
+	en := mappingValue(n, "end_date")
+	if en != nil && en.Value != "" {
+		t, present, err := parseFlexibleDate(en.Value)
+		if err != nil {
+			v.add(SeverityError, en, joinPath(path, "end_date"), "%v", err)
+		}
+		end, endPresent = t, present
+	}
+	if !start.IsZero() && !end.IsZero() && !endPresent && end.Before(start) {
+		v.add(SeverityError, en, joinPath(path, "end_date"), "end date %q is before start date", en.Value)
+	}
+}
+
+// entries calls check for every mapping in the list at key.
+func (v *validator) entries(root *yaml.Node, key string, check func(item *yaml.Node, path string)) {
+	list := mappingValue(root, key)
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, item := range list.Content {
+		if item.Kind == yaml.MappingNode {
+			check(item, fmt.Sprintf("%s.%d", key, i))
+		}
+	}
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlFields maps the yaml keys of a struct type to their field types.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	out := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if !sf.IsExported() || tag == "" || tag == "-" {
+			continue
+		}
+		out[tag] = sf.Type
+	}
+	return out
+}
+
+var phoneRe = regexp.MustCompile(`^\+?[0-9 ().\-]+((x|ext\.?)\s*[0-9]+)?$`)
+
+func validPhone(s string) bool {
+	digits := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15 && phoneRe.MatchString(strings.TrimSpace(s))
+}
+
+// closestKey returns the known key within a small edit distance of key, if any.
+func closestKey(key string, known map[string]reflect.Type) string {
+	best, bestDist := "", 3
+	for k := range known {
+		if d := editDistance(strings.ToLower(key), k); d < bestDist || (d == bestDist && k < best) {
+			best, bestDist = k, d
+		}
+	}
+	if bestDist > 2 {
+		return ""
+	}
+	return best
+}
+
+// editDistance is the Damerau-Levenshtein (optimal string alignment) distance,
+// so swapped letters like "compnay" count as one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
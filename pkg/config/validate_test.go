@@ -0,0 +1,134 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate_SampleConfigIsClean(t *testing.T) {
+	diags, err := ValidateFile(filepath.Join("..", "..", "config.yml"))
+	if err != nil {
+		t.Fatalf("ValidateFile: %v", err)
+	}
+	if len(diags) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", diags)
+	}
+}
+
+func TestValidate_Diagnostics(t *testing.T) {
+	src := `name: Jane
+email: jane@@example
+phone: "call me"
+website: "not a url"
+compnay_to_apply_to: ACME
+HiringManager: Ada
+education:
+  - degree: B.S.
+    start_date: "2018"
+    end_date: "Smarch 2022"
+experience:
+  - compnay: Go Corp
+    position: Engineer
+    start_date: "June 2022"
+    end_date: "May 2021"
+  - company: Test Inc.
+    position: Intern
+    start_date: "Present"
+projects:
+  - name: Covlet
+    url: "https://github.com/johndoe/covlet"
+`
+	diags := Validate([]byte(src))
+	want := []struct {
+		line     int
+		sev      Severity
+		path     string
+		contains string
+	}{
+		{2, SeverityError, "email", "invalid email"},
+		{3, SeverityWarning, "phone", "malformed"},
+		{4, SeverityError, "website", "invalid URL"},
+		{5, SeverityWarning, "compnay_to_apply_to", `did you mean "company_to_apply_to"`},
+		{8, SeverityError, "education.0.institution", "required"},
+		{10, SeverityError, "education.0.end_date", "unrecognized date"},
+		{12, SeverityError, "experience.0.compnay", `did you mean "company"`},
+		{12, SeverityError, "experience.0.company", "required"},
+		{15, SeverityError, "experience.0.end_date", "before start date"},
+		{18, SeverityError, "experience.1.start_date", "cannot be"},
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d:\n%v", len(diags), len(want), diags)
+	}
+	for i, w := range want {
+		d := diags[i]
+		if d.Line != w.line || d.Severity != w.sev || d.Path != w.path || !strings.Contains(d.Message, w.contains) {
+			t.Fatalf("diagnostic %d = %v; want line %d %s %s containing %q", i, d, w.line, w.sev, w.path, w.contains)
+		}
+	}
+	if !HasErrors(diags) {
+		t.Fatalf("expected HasErrors to be true")
+	}
+}
+
+func TestValidate_SyntaxAndTypeErrors(t *testing.T) {
+	diags := Validate([]byte("name: Jane\nemail: [a\n"))
+	if len(diags) != 1 || diags[0].Severity != SeverityError || diags[0].Line == 0 {
+		t.Fatalf("expected a single syntax error, got %v", diags)
+	}
+	diags = Validate([]byte("name: Jane\nemail: jane@example.com\nskills:\n  - name: Go\n"))
+	if len(diags) != 1 || diags[0].Line != 4 {
+		t.Fatalf("expected a type error on line 4, got %v", diags)
+	}
+}
+
+func TestValidateLayerFile(t *testing.T) {
+	if err := SetMainDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	layer := writeValues(t, ValuesDir(), "companies/acme.yml", "company_to_apply_to: ACME\nexperience:\n  - position: Lead\n")
+	if !IsLayerFile(layer) {
+		t.Fatalf("expected %s to be a layer", layer)
+	}
+	diags, err := ValidateLayerFile(layer)
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("expected a partial layer to be clean, got %v (%v)", diags, err)
+	}
+	if diags, _ := ValidateFile(layer); !HasErrors(diags) {
+		t.Fatalf("expected the required fields to be checked for a full resume")
+	}
+
+	bad := writeValues(t, ValuesDir(), "bad.yml", "email: nope\ncompnay_to_apply_to: ACME\n")
+	if diags, _ := ValidateLayerFile(bad); len(diags) != 2 {
+		t.Fatalf("expected format and unknown key checks on a layer, got %v", diags)
+	}
+
+	for _, base := range []string{ResumePath(), filepath.Join(ProfilesDir(), "sre.yml"), filepath.Join(t.TempDir(), "acme.yml")} {
+		if IsLayerFile(base) {
+			t.Errorf("expected %s not to be a layer", base)
+		}
+	}
+}
+
+func TestParseFlexibleDate(t *testing.T) {
+	cases := map[string]string{
+		"2018":       "2018-01",
+		"June 2022":  "2022-06",
+		"jun 2022":   "2022-06",
+		"2022-06":    "2022-06",
+		"2022-06-15": "2022-06",
+		"06/2022":    "2022-06",
+	}
+	for in, want := range cases {
+		got, present, err := parseFlexibleDate(in)
+		if err != nil || present || got.Format("2006-01") != want {
+			t.Fatalf("parseFlexibleDate(%q) = %v, %v, %v; want %s", in, got, present, err, want)
+		}
+	}
+	if _, present, err := parseFlexibleDate("Present"); err != nil || !present {
+		t.Fatalf("expected Present to parse as ongoing, got %v %v", present, err)
+	}
+	if _, _, err := parseFlexibleDate("someday"); err == nil {
+		t.Fatalf("expected an error for an unrecognized date")
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergeNode copies the values of src into dst while keeping dst's comments,
+// key order and scalar styles where possible. Keys missing from src are
+// dropped from dst; new keys are appended in src order unless their value is
+// empty (see dropEmpty). References in dst (see resolveReferences) are kept
+// while their resolved value in keep still matches src, and replaced by the
+// literal value otherwise.
+func mergeNode(dst, src *yaml.Node, keep refs) {
+	if resolved, ok := keep[dst]; ok {
+		if sameValue(resolved, src) {
+			return
+		}
+		// the reference is replaced by its new literal value below
+		delete(keep, dst)
+	} else if dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode {
+		mergeMapping(dst, src, keep)
+		return
+	}
+	if dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode {
+		n := len(src.Content)
+		if len(dst.Content) > n {
+			dst.Content = dst.Content[:n]
+		}
+		for i, item := range src.Content {
+			if i < len(dst.Content) {
+				mergeNode(dst.Content[i], item, keep)
+			} else {
+				dropEmpty(item)
+				dst.Content = append(dst.Content, item)
+			}
+		}
+		return
+	}
+
+	if dst.Kind == yaml.ScalarNode && src.Kind == yaml.ScalarNode && dst.Value == src.Value {
+		// unchanged value: keep it exactly as written, e.g. an unquoted 2018
+		return
+	}
+	dropEmpty(src)
+	style := src.Style
+	if style == 0 && dst.Kind == src.Kind && dst.Tag == src.Tag {
+		style = dst.Style
+	}
+	dst.Kind = src.Kind
+	dst.Tag = src.Tag
+	dst.Value = src.Value
+	dst.Content = src.Content
+	dst.Style = style
+	dst.Anchor = ""
+	dst.Alias = nil
+}
+
+func mergeMapping(dst, src *yaml.Node, keep refs) {
+	srcIdx := map[string]int{}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		srcIdx[src.Content[i].Value] = i
+	}
+	used := map[string]bool{}
+	content := make([]*yaml.Node, 0, len(src.Content))
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key := dst.Content[i].Value
+		j, ok := srcIdx[key]
+		if !ok {
+			continue
+		}
+		used[key] = true
+		mergeNode(dst.Content[i+1], src.Content[j+1], keep)
+		content = append(content, dst.Content[i], dst.Content[i+1])
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		if used[src.Content[i].Value] {
+			continue
+		}
+		if dropEmpty(src.Content[i+1]); isEmptyNode(src.Content[i+1]) {
+			continue
+		}
+		content = append(content, src.Content[i], src.Content[i+1])
+	}
+	dst.Content = content
+}
+
+// dropEmpty removes the mapping keys under n whose values are empty, at any
+// depth, so that a value new to the file does not spell out every unset
+// field as phone: "" or projects: [].
+func dropEmpty(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			dropEmpty(item)
+		}
+	case yaml.MappingNode:
+		content := n.Content[:0]
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if dropEmpty(n.Content[i+1]); isEmptyNode(n.Content[i+1]) {
+				continue
+			}
+			content = append(content, n.Content[i], n.Content[i+1])
+		}
+		n.Content = content
+	}
+}
+
+// encodeNode renders a YAML node with the two-space indentation used by config.yml.
+func encodeNode(n *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(n); err != nil {
+		return nil, fmt.Errorf("encode yaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFileAtomic writes data to a temp file next to filename and renames it
+// into place, so readers never observe a partially written file. The mode of
+// an existing file is kept.
+func writeFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	if fi, err := os.Stat(filename); err == nil {
+		perm = fi.Mode().Perm()
+	}
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpName) }
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmpName, filename); err != nil {
+		cleanup()
+		return err
+	}
+	return nil
+}
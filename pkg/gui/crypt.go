@@ -0,0 +1,100 @@
+package gui
+
+import (
+	"covlet/pkg/config"
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// isLocked reports whether err means the values file is encrypted and the
+// passphrase is missing or wrong, e.g. a stale one from --passphrase-file or
+// COVLET_PASSPHRASE_FILE.
+func isLocked(err error) bool {
+	return errors.Is(err, config.ErrPassphraseRequired) || errors.Is(err, config.ErrWrongPassphrase)
+}
+
+// showUnlock asks for the passphrase of an encrypted values file and loads
+// it, asking again after a wrong passphrase. onUnlock, if not nil, is called
+// once the file is loaded.
+func (e *TextEditor) showUnlock(w fyne.Window, onUnlock func()) {
+	entry := widget.NewPasswordEntry()
+	dialog.ShowForm("Unlock Values File", "Unlock", "Cancel",
+		[]*widget.FormItem{{Text: "Passphrase", Widget: entry, HintText: "Your resume values file is encrypted"}},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			config.SetPassphrase(entry.Text)
+			e.cfg = nil
+			if _, err := e.loadConfig(); err != nil {
+				if errors.Is(err, config.ErrWrongPassphrase) {
+					dialog.ShowInformation("Unlock Values File", "Wrong passphrase, try again.", w)
+					e.showUnlock(w, onUnlock)
+					return
+				}
+				dialog.ShowError(err, w)
+				return
+			}
+			if onUnlock != nil {
+				onUnlock()
+			}
+		}, w)
+}
+
+// encryptionItem returns the File menu item that encrypts the values file
+// or, when it is already encrypted, removes the encryption.
+func (e *TextEditor) encryptionItem(w fyne.Window) *fyne.MenuItem {
+	return fyne.NewMenuItem("Encrypt Values File…", func() {
+		cfg, err := e.loadConfig()
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error loading values: %w", err), w)
+			return
+		}
+		if cfg.Encrypted() {
+			dialog.ShowConfirm("Remove Encryption", "The values file is encrypted. Save it as plain text instead?", func(ok bool) {
+				if ok {
+					e.saveEncrypted(w, cfg, false)
+				}
+			}, w)
+			return
+		}
+
+		pass, confirm := widget.NewPasswordEntry(), widget.NewPasswordEntry()
+		dialog.ShowForm("Encrypt Values File", "Encrypt", "Cancel",
+			[]*widget.FormItem{
+				{Text: "Passphrase", Widget: pass, HintText: "Needed every time Covlet starts; it cannot be recovered"},
+				{Text: "Confirm", Widget: confirm},
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if pass.Text == "" || pass.Text != confirm.Text {
+					dialog.ShowError(errors.New("the passphrases are empty or do not match"), w)
+					return
+				}
+				config.SetPassphrase(pass.Text)
+				e.saveEncrypted(w, cfg, true)
+			}, w)
+	})
+}
+
+func (e *TextEditor) saveEncrypted(w fyne.Window, cfg *config.Config, on bool) {
+	if err := cfg.SetEncrypted(on); err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	if err := cfg.Save(); err != nil {
+		dialog.ShowError(fmt.Errorf("error saving %s: %w", cfg.Path(), err), w)
+		return
+	}
+	state := "encrypted"
+	if !on {
+		state = "saved as plain text"
+	}
+	dialog.ShowInformation("Values File", fmt.Sprintf("%s is now %s.", cfg.Path(), state), w)
+}
@@ -3,7 +3,10 @@ package gui
 import (
 	"covlet/pkg/config"
 	"covlet/pkg/internal"
+	"errors"
 	"fmt"
+	"io/fs"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -26,6 +29,17 @@ type TextEditor struct {
 	// tracked variables and user overrides
 	tmplVars  []string
 	overrides map[string]string
+	// cfg is the loaded resume config, shared with the resume editor panel
+	cfg *config.Config
+	// layers are the values files (relative to ValuesDir) merged on top of cfg
+	layers []string
+	// problemsWin is the open problems panel, if any
+	problemsWin fyne.Window
+	// siblings also loads the templates next to the open file when rendering
+	siblings bool
+	// strict renders with Letter.RenderStrict, listing unresolved references
+	// in the problems panel
+	strict bool
 }
 
 // NewEditor returns the editor container and the underlying text entry widget
@@ -91,35 +105,11 @@ func NewEditor(w fyne.Window) (*fyne.Container, *TextEditor) {
 
 	// create a new window to host rendering at the bottom of the app
 	renderButton := widget.NewButton("Render", func() {
-		configFile, err := config.LoadConfig("config.yml")
-		if err != nil {
-			fmt.Printf("error loading config: %v", err)
-			return
-		}
-
-		// Build data by applying overrides on top of config defaults
-		data := applyOverrides(configFile.Resume, editor.overrides)
-
-		// render the template
-		r, err := internal.RenderEditor(editor.ConvertText(), data)
-		if err != nil {
-			fmt.Printf("error rendering template: %v", err)
-			return
-		}
-		// todo: render to new bottom window (idk)
-		text := widget.NewMultiLineEntry()
-		text.SetText(string(r))
-        rContent := container.NewBorder(nil, nil, nil, nil, text)
-        rWindow := fyne.CurrentApp().NewWindow("Rendered Cover Letter")
-        // pass a getter so the menu can export the latest text
-        rWindow.SetMainMenu(renderMenu(rWindow, func() string { return text.Text }))
-        rWindow.SetContent(rContent)
-        rWindow.Resize(fyne.NewSize(1000, 700))
-        rWindow.Show()
-    })
+		editor.render(w)
+	})
 
 	// Build variable sidebar on the right
-	editor.initVarSidebar()
+	editor.initVarSidebar(w)
 	mainWindow := container.NewHSplit(tabs, editor.varSidebar)
 	mainWindow.Offset = 0.75
 	// Compose main editor area with right sidebar
@@ -192,22 +182,157 @@ func newEditor() *TextEditor {
 	return e
 }
 
-func (e *TextEditor) ConvertText() *template.Template {
-	text := e.editor.Text
-	return template.Must(template.New("").Parse(text))
+// render executes the current template with the resume values and overrides
+// and shows the result in a new window. Without a resume the setup wizard runs first.
+func (e *TextEditor) render(w fyne.Window) {
+	configFile, err := e.loadConfig()
+	if errors.Is(err, fs.ErrNotExist) {
+		// no resume yet: run the first-time setup, then render with the new values
+		showSetupWizard(func(cfg *config.Config) {
+			e.cfg = cfg
+			e.render(w)
+		})
+		return
+	}
+	if isLocked(err) {
+		// encrypted and not unlocked yet: ask, then render
+		e.showUnlock(w, func() { e.render(w) })
+		return
+	}
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading config: %w", err), w)
+		return
+	}
+
+	// surface values problems without blocking the render
+	if problems, err := e.validateValues(); err == nil && hasErrorProblems(problems) {
+		e.showProblems(problems)
+	}
+
+	// Build data from the values layers with the sidebar overrides on top
+	stack, err := e.valuesStack(configFile)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading values layers: %w", err), w)
+		return
+	}
+	stack, err = stack.WithOverrides(e.activeOverrides())
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("invalid overrides: %w", err), w)
+		return
+	}
+
+	// render the template, filling its front matter defaults
+	letter, err := e.renderer().Load(e.currentPath, e.editor.Text)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error parsing template: %w", err), w)
+		return
+	}
+	resume, err := letter.Meta.Apply(stack.Resume)
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	data := resume.Data()
+	var r []byte
+	if e.strict {
+		r, err = letter.RenderStrict(data)
+	} else {
+		r, err = internal.RenderEditor(letter.Template, data)
+	}
+	var serr *internal.StrictError
+	if errors.As(err, &serr) && len(serr.Unresolved) > 0 {
+		e.showProblems(strictProblems(serr))
+		if serr.Err == nil {
+			return
+		}
+	}
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error rendering template: %w", err), w)
+		return
+	}
+	var defaults exportDefaults
+	defaults.Title, _ = letter.Meta.DocumentTitle(data)
+	defaults.FileName, _ = letter.Meta.OutputName(data)
+	// todo: render to new bottom window (idk)
+	text := widget.NewMultiLineEntry()
+	text.SetText(string(r))
+	rContent := container.NewBorder(nil, nil, nil, nil, text)
+	rWindow := fyne.CurrentApp().NewWindow("Rendered Cover Letter")
+	// pass a getter so the menu can export the latest text
+	rWindow.SetMainMenu(renderMenu(rWindow, func() string { return text.Text }, defaults))
+	rWindow.SetContent(rContent)
+	rWindow.Resize(fyne.NewSize(1000, 700))
+	rWindow.Show()
+}
+
+// loadConfig returns the editor's resume config, loading it on first use.
+func (e *TextEditor) loadConfig() (*config.Config, error) {
+	if e.cfg != nil {
+		return e.cfg, nil
+	}
+	cfg, err := loadResumeConfig()
+	if err != nil {
+		return nil, err
+	}
+	e.cfg = cfg
+	return cfg, nil
+}
+
+// loadResumeConfig loads the resume values file chosen by config.ResolveConfigFile.
+func loadResumeConfig() (*config.Config, error) {
+	path, err := config.ResolveConfigFile("")
+	if err != nil {
+		return nil, err
+	}
+	return config.LoadConfig(path)
+}
+
+// ConvertText parses the editor text together with the partials under
+// templates/partials, the layouts it extends and the open file's siblings,
+// if enabled.
+func (e *TextEditor) ConvertText() (*template.Template, error) {
+	return e.renderer().Parse(e.currentPath, e.editor.Text)
+}
+
+func (e *TextEditor) renderer() *internal.Renderer {
+	return &internal.Renderer{TemplatesDir: config.TemplatesDir(), Siblings: e.siblings}
+}
+
+// showBlocks lists the layouts the editor text extends and the file each of
+// its blocks comes from.
+func (e *TextEditor) showBlocks(w fyne.Window) {
+	l, err := e.renderer().Load(e.currentPath, e.editor.Text)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error parsing template: %w", err), w)
+		return
+	}
+	var b strings.Builder
+	if len(l.Layouts) > 0 {
+		fmt.Fprintf(&b, "Extends: %s\n\n", strings.Join(l.Layouts, " → "))
+	}
+	for _, name := range l.BlockNames() {
+		fmt.Fprintf(&b, "%s — %s\n", name, l.Blocks[name])
+	}
+	if b.Len() == 0 {
+		b.WriteString("This template has no layout or blocks.")
+	}
+	dialog.ShowInformation("Template Blocks", strings.TrimSpace(b.String()), w)
 }
 
 // initVarSidebar initializes the sidebar container used to track {{ }} variables
-func (e *TextEditor) initVarSidebar() {
+func (e *TextEditor) initVarSidebar(w fyne.Window) {
 	title := widget.NewLabel("Template Variables")
 	title.Alignment = fyne.TextAlignLeading
 	e.varForm = &widget.Form{}
 	info := widget.NewLabel("override any default values")
+	addBtn := widget.NewButton("Add Override…", func() {
+		e.showAddOverride(w)
+	})
 	clearBtn := widget.NewButton("Clear Overrides", func() {
 		e.overrides = map[string]string{}
 		e.refreshVarSidebar()
 	})
-	e.varSidebar = container.NewBorder(title, container.NewVBox(info, clearBtn), nil, nil, container.NewVScroll(e.varForm))
+	e.varSidebar = container.NewBorder(title, container.NewVBox(info, addBtn, clearBtn), nil, nil, container.NewVScroll(e.varForm))
 	e.varSidebar.Resize(fyne.NewSize(50, 100))
 	// initial populate
 	e.refreshVarSidebar()
@@ -222,9 +347,9 @@ func (e *TextEditor) refreshVarSidebar() {
 	// keep order stable
 	e.tmplVars = vars
 
-	// rebuild form
+	// rebuild form: template variables first, then any extra override paths
 	e.varForm.Items = nil
-	for _, v := range vars {
+	for _, v := range sidebarKeys(vars, e.overrides) {
 		name := v // capture
 		val := e.overrides[name]
 		entry := widget.NewEntry()
@@ -240,6 +365,57 @@ func (e *TextEditor) refreshVarSidebar() {
 	e.varForm.Refresh()
 }
 
+// showAddOverride prompts for a dotted override path such as Experience.0.Company
+// and adds it to the sidebar.
+func (e *TextEditor) showAddOverride(w fyne.Window) {
+	path := widget.NewEntry()
+	path.SetPlaceHolder("Experience.0.Company")
+	value := widget.NewEntry()
+	dialog.ShowForm("Add Override", "Add", "Cancel",
+		[]*widget.FormItem{{Text: "Path", Widget: path}, {Text: "Value", Widget: value}},
+		func(ok bool) {
+			key := strings.TrimSpace(path.Text)
+			if !ok || key == "" {
+				return
+			}
+			if e.overrides == nil {
+				e.overrides = map[string]string{}
+			}
+			e.overrides[key] = value.Text
+			e.refreshVarSidebar()
+		}, w)
+}
+
+// activeOverrides returns the sidebar overrides that have a value; empty
+// entries fall back to the config defaults.
+func (e *TextEditor) activeOverrides() map[string]string {
+	out := map[string]string{}
+	for k, v := range e.overrides {
+		if v != "" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// sidebarKeys lists the template variables followed by any override paths not
+// already among them, in sorted order.
+func sidebarKeys(vars []string, overrides map[string]string) []string {
+	keys := append([]string{}, vars...)
+	seen := map[string]bool{}
+	for _, v := range vars {
+		seen[v] = true
+	}
+	var extra []string
+	for k := range overrides {
+		if !seen[k] {
+			extra = append(extra, k)
+		}
+	}
+	sort.Strings(extra)
+	return append(keys, extra...)
+}
+
 // parseTopLevelVars extracts top-level variable names referenced as {{ .Name }} etc.
 // It attempts to collect unique identifiers that immediately follow a dot.
 func parseTopLevelVars(s string) []string {
@@ -301,38 +477,3 @@ func isIdentStart(b byte) bool {
 func isIdentPart(b byte) bool {
 	return isIdentStart(b) || (b >= '0' && b <= '9')
 }
-
-// applyOverrides returns a copy of the Resume with string fields overridden by overrides map
-func applyOverrides(in config.Resume, overrides map[string]string) config.Resume {
-	if overrides == nil || len(overrides) == 0 {
-		return in
-	}
-	// copy value
-	out := in
-	// manually override known top-level string fields
-	if v, ok := overrides["Name"]; ok {
-		out.Name = v
-	}
-	if v, ok := overrides["Email"]; ok {
-		out.Email = v
-	}
-	if v, ok := overrides["Phone"]; ok {
-		out.Phone = v
-	}
-	if v, ok := overrides["Address"]; ok {
-		out.Address = v
-	}
-	if v, ok := overrides["Website"]; ok {
-		out.Website = v
-	}
-	if v, ok := overrides["Github"]; ok {
-		out.Github = v
-	}
-	if v, ok := overrides["CompanyToApplyTo"]; ok {
-		out.CompanyToApplyTo = v
-	}
-	if v, ok := overrides["RoleToApplyTo"]; ok {
-		out.RoleToApplyTo = v
-	}
-	return out
-}
@@ -0,0 +1,41 @@
+package gui
+
+import (
+	"covlet/pkg/config"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// showExport asks for a file and writes the loaded resume to it with encode,
+// e.g. config.ExportJSONResume.
+func (e *TextEditor) showExport(w fyne.Window, fileName string, encode func(config.Resume) ([]byte, error)) {
+	cfg, err := e.loadConfig()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading config: %w", err), w)
+		return
+	}
+	b, err := encode(cfg.Resume)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error exporting resume: %w", err), w)
+		return
+	}
+	b = append(b, '\n')
+
+	dlg := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if wc == nil {
+			return
+		}
+		defer wc.Close()
+		if _, err := wc.Write(b); err != nil {
+			dialog.ShowError(err, w)
+		}
+	}, w)
+	dlg.SetFileName(fileName)
+	dlg.Show()
+}
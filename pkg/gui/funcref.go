@@ -0,0 +1,26 @@
+package gui
+
+import (
+	"covlet/pkg/internal"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showFuncReference lists the template helpers with a usage line and an
+// example each.
+func showFuncReference(w fyne.Window) {
+	var b strings.Builder
+	for _, d := range internal.FuncDocs {
+		fmt.Fprintf(&b, "**%s** — %s\n\n`%s`\n\n", d.Usage, d.Summary, d.Example)
+	}
+	ref := widget.NewRichTextFromMarkdown(b.String())
+	ref.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(ref)
+	scroll.SetMinSize(fyne.NewSize(600, 450))
+	dialog.ShowCustom("Template Functions", "Close", scroll, w)
+}
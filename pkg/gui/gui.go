@@ -2,12 +2,16 @@ package gui
 
 import (
 	"covlet/pkg/config"
+	"covlet/pkg/internal"
+	"errors"
 	"fmt"
 	"image/color"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -35,8 +39,10 @@ func (s *smallTheme) Size(n fyne.ThemeSizeName) float32 {
 	return sz
 }
 
+// Run opens the editor window and blocks until it is closed. A main dir
+// already chosen, e.g. by the --home flag, is kept.
 func Run() error {
-	config.InitMainDir()
+	config.GetMainDir()
 	a := app.New()
 	// apply smaller text theme (one size smaller)
 	a.Settings().SetTheme(&smallTheme{base: theme.DefaultTheme()})
@@ -69,6 +75,18 @@ func Run() error {
 	toggleLeft := true
 	toggleRight := true
 
+	importItem := fyne.NewMenuItem("Import", nil)
+	importItem.ChildMenu = fyne.NewMenu("",
+		fyne.NewMenuItem("JSON Resume…", func() { editor.showImportJSONResume(w) }),
+		fyne.NewMenuItem("LinkedIn Data Export…", func() { editor.showImportLinkedIn(w) }),
+	)
+
+	exportItem := fyne.NewMenuItem("Export", nil)
+	exportItem.ChildMenu = fyne.NewMenu("",
+		fyne.NewMenuItem("JSON Resume…", func() { editor.showExport(w, "resume.json", config.ExportJSONResume) }),
+		fyne.NewMenuItem("Plain JSON…", func() { editor.showExport(w, "covlet.json", config.ExportJSON) }),
+	)
+
 	fileMenu := fyne.NewMenu("File",
 		fyne.NewMenuItem("New", func() { editor.new(w) }),
 		fyne.NewMenuItem("Open Folder…", func() {
@@ -80,11 +98,15 @@ func Run() error {
 				if listable == nil {
 					return
 				}
-				if err := config.SetMainDir(listable.Path()); err != nil {
+				// remember the folder so it is used again on the next start
+				if err := config.RememberMainDir(listable.Path()); err != nil {
 					dialog.ShowError(err, w)
 					return
 				}
 				_, _ = config.EnsureTemplatesDir()
+				// values live in the new home too; reload them on next use
+				editor.cfg = nil
+				editor.layers = nil
 				lRoot, rRoot := computeRoots()
 				leftTree.Root = lRoot
 				rightTree.Root = rRoot
@@ -93,6 +115,13 @@ func Run() error {
 			}, w)
 			dlg.Show()
 		}),
+		importItem,
+		exportItem,
+		fyne.NewMenuItem("Edit Resume…", func() { editor.showResumeEditor(w) }),
+		fyne.NewMenuItem("Values Layers…", func() { editor.showValuesLayers(w) }),
+		fyne.NewMenuItem("Validate Values…", func() { editor.checkValues(w) }),
+		editor.encryptionItem(w),
+		fyne.NewMenuItemSeparator(),
 		fyne.NewMenuItem("Save", func() { _ = editor.save(w) }),
 		fyne.NewMenuItem("Save As…", func() { _ = editor.saveAs(w) }),
 		fyne.NewMenuItemSeparator(),
@@ -128,7 +157,22 @@ func Run() error {
 		fyne.NewMenuItem("Find", func() { editor.find(w) }),
 	)
 
-	viewMenu := fyne.NewMenu("View",
+	// partials are always loaded; sibling templates only on request
+	var viewMenu *fyne.Menu
+	siblingsItem := fyne.NewMenuItem("Load Sibling Templates", nil)
+	siblingsItem.Action = func() {
+		editor.siblings = !editor.siblings
+		siblingsItem.Checked = editor.siblings
+		viewMenu.Refresh()
+	}
+	strictItem := fyne.NewMenuItem("Strict Rendering", nil)
+	strictItem.Action = func() {
+		editor.strict = !editor.strict
+		strictItem.Checked = editor.strict
+		viewMenu.Refresh()
+	}
+
+	viewMenu = fyne.NewMenu("View",
 		fyne.NewMenuItem("Toggle Left Sidebar", func() {
 			toggleLeft = !toggleLeft
 			if toggleLeft {
@@ -152,20 +196,42 @@ func Run() error {
 			mainSplit.Offset = 0.75
 		}),
 		fyne.NewMenuItemSeparator(),
+		siblingsItem,
+		strictItem,
+		fyne.NewMenuItem("Template Blocks…", func() { editor.showBlocks(w) }),
+		fyne.NewMenuItemSeparator(),
 		fyne.NewMenuItem("Light Theme", func() { a.Settings().SetTheme(&smallTheme{base: theme.LightTheme()}) }),
 		fyne.NewMenuItem("Dark Theme", func() { a.Settings().SetTheme(&smallTheme{base: theme.DarkTheme()}) }),
 	)
 
 	helpMenu := fyne.NewMenu("Help",
 		fyne.NewMenuItem("About", func() { dialog.ShowInformation("About", "Covlet - Cover Letter Templates Editor", w) }),
+		fyne.NewMenuItem("Template Functions", func() { showFuncReference(w) }),
 		fyne.NewMenuItem("Shortcuts", func() {
 			dialog.ShowInformation("Shortcuts", "Ctrl+S Save\nCtrl+N New\nCtrl+F Find\nCtrl+U Undo\nCtrl+R Redo", w)
 		}),
 	)
 
-	w.SetMainMenu(fyne.NewMainMenu(fileMenu, editMenu, viewMenu, helpMenu))
+	// the Profile menu is rebuilt whenever profiles change
+	var mainMenu *fyne.MainMenu
+	var refreshProfiles func()
+	refreshProfiles = func() {
+		mainMenu.Items[3] = editor.profileMenu(w, refreshProfiles)
+		mainMenu.Refresh()
+	}
+	mainMenu = fyne.NewMainMenu(fileMenu, editMenu, viewMenu, editor.profileMenu(w, refreshProfiles), helpMenu)
+	w.SetMainMenu(mainMenu)
 
 	w.SetContent(mainSplit)
+
+	// first run: walk through creating the resume values file; an encrypted
+	// one is unlocked first
+	if _, err := editor.loadConfig(); errors.Is(err, fs.ErrNotExist) {
+		showSetupWizard(func(cfg *config.Config) { editor.cfg = cfg })
+	} else if isLocked(err) {
+		editor.showUnlock(w, nil)
+	}
+
 	w.ShowAndRun()
 	return nil
 }
@@ -197,19 +263,30 @@ func fileTree(root string, e *TextEditor, w fyne.Window) *widget.Tree {
 		}
 		return fi.IsDir()
 	}
+	// rows are redrawn on every scroll, so front matter is read once per change
+	summaries := frontMatterSummaries{}
 	var t *widget.Tree
 	create := func(branch bool) fyne.CanvasObject {
 		name := widget.NewLabel("")
+		// title and tags from the template's front matter
+		meta := widget.NewLabel("")
+		meta.Importance = widget.LowImportance
+		meta.TextStyle = fyne.TextStyle{Italic: true}
 		more := widget.NewButtonWithIcon("", theme.MoreVerticalIcon(), func() {})
 		more.Importance = widget.LowImportance
-		c := container.New(layout.NewHBoxLayout(), name, layout.NewSpacer(), more)
+		c := container.New(layout.NewHBoxLayout(), name, meta, layout.NewSpacer(), more)
 		return c
 	}
 	update := func(uid string, branch bool, obj fyne.CanvasObject) {
 		c := obj.(*fyne.Container)
 		name := c.Objects[0].(*widget.Label)
-		more := c.Objects[2].(*widget.Button)
+		meta := c.Objects[1].(*widget.Label)
+		more := c.Objects[3].(*widget.Button)
 		name.SetText(filepath.Base(uid))
+		meta.SetText("")
+		if !branch {
+			meta.SetText(summaries.get(uid))
+		}
 		if branch {
 			more.Show()
 			// bind click to create a file in this directory
@@ -253,6 +330,37 @@ func fileTree(root string, e *TextEditor, w fyne.Window) *widget.Tree {
 	return t
 }
 
+// frontMatterSummaries caches the front matter summary shown next to each
+// file in the tree, keyed by path and refreshed when the file changes.
+type frontMatterSummaries map[string]frontMatterSummary
+
+type frontMatterSummary struct {
+	modTime time.Time
+	size    int64
+	text    string
+}
+
+// get returns the summary of the template at path, reading its front matter
+// only when the file is new to the cache or was modified since.
+func (c frontMatterSummaries) get(path string) string {
+	fi, err := os.Stat(path)
+	if err != nil {
+		delete(c, path)
+		return ""
+	}
+	if s, ok := c[path]; ok && s.modTime.Equal(fi.ModTime()) && s.size == fi.Size() {
+		return s.text
+	}
+	s := frontMatterSummary{modTime: fi.ModTime(), size: fi.Size()}
+	if fm, err := internal.ReadFrontMatter(path); err != nil {
+		s.text = "invalid front matter"
+	} else {
+		s.text = fm.Summary()
+	}
+	c[path] = s
+	return s.text
+}
+
 // showCreateFileDialog prompts for a new file name in the given directory and creates it.
 func showCreateFileDialog(w fyne.Window, dir string, t *widget.Tree) {
 	nameEntry := widget.NewEntry()
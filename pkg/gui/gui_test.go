@@ -1,8 +1,14 @@
 package gui
 
 import (
+    "covlet/pkg/config"
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
     "strings"
     "testing"
+    "time"
 )
 
 func TestParseTopLevelVars_Basic(t *testing.T) {
@@ -42,3 +48,76 @@ func TestSanitizeFileName(t *testing.T) {
         }
     }
 }
+
+func TestMoveItem(t *testing.T) {
+    cases := []struct {
+        from, to int
+        want     string
+    }{
+        {0, 2, "b,c,a,d"},
+        {3, 1, "a,d,b,c"},
+        {1, 1, "a,b,c,d"},
+        {-1, 2, "a,b,c,d"},
+        {0, 4, "a,b,c,d"},
+    }
+    for _, c := range cases {
+        got := strings.Join(moveItem([]string{"a", "b", "c", "d"}, c.from, c.to), ",")
+        if got != c.want {
+            t.Fatalf("moveItem(%d, %d) = %s; want %s", c.from, c.to, got, c.want)
+        }
+    }
+}
+
+func TestSidebarKeys(t *testing.T) {
+    got := sidebarKeys([]string{"Name", "Experience"}, map[string]string{"Skills.0": "Go", "Name": "Jane", "Experience.0.Company": "ACME"})
+    want := "Name,Experience,Experience.0.Company,Skills.0"
+    if strings.Join(got, ",") != want {
+        t.Fatalf("sidebarKeys = %v; want %s", got, want)
+    }
+}
+
+func TestOrderedLayers(t *testing.T) {
+    files := []string{"acme.yml", "roles/sre.yml", "zeta.yml"}
+    got := orderedLayers(files, []string{"zeta.yml", "missing.yml", "acme.yml"})
+    if strings.Join(got, ",") != "acme.yml,zeta.yml" {
+        t.Fatalf("orderedLayers = %v", got)
+    }
+}
+
+func TestIsLocked(t *testing.T) {
+    for _, err := range []error{config.ErrPassphraseRequired, fmt.Errorf("load: %w", config.ErrWrongPassphrase)} {
+        if !isLocked(err) {
+            t.Fatalf("expected %v to ask for the passphrase", err)
+        }
+    }
+    if isLocked(errors.New("boom")) || isLocked(nil) {
+        t.Fatalf("expected other errors not to ask for the passphrase")
+    }
+}
+
+func TestFrontMatterSummaries(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "letter.tpl")
+    write := func(title string, mod time.Time) {
+        if err := os.WriteFile(path, []byte("---\ntitle: "+title+"\n---\nHi"), 0o644); err != nil {
+            t.Fatal(err)
+        }
+        if err := os.Chtimes(path, mod, mod); err != nil {
+            t.Fatal(err)
+        }
+    }
+    mod := time.Now().Add(-time.Hour)
+    write("Alpha", mod)
+    c := frontMatterSummaries{}
+    if got := c.get(path); !strings.Contains(got, "Alpha") {
+        t.Fatalf("get = %q; want the title", got)
+    }
+    // same size and modification time: served from the cache
+    write("Omega", mod)
+    if got := c.get(path); !strings.Contains(got, "Alpha") {
+        t.Fatalf("get = %q; want the cached title", got)
+    }
+    write("Omega", mod.Add(time.Minute))
+    if got := c.get(path); !strings.Contains(got, "Omega") {
+        t.Fatalf("get = %q; want the title re-read after a change", got)
+    }
+}
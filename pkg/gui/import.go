@@ -0,0 +1,151 @@
+package gui
+
+import (
+	"covlet/pkg/config"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showImportJSONResume asks for a jsonresume.org resume.json and replaces the
+// resume data of the loaded values file with it.
+func (e *TextEditor) showImportJSONResume(w fyne.Window) {
+	showImportFile(w, ".json", config.ImportJSONResume, func(imported config.Resume, unmapped []string) {
+		e.applyImport(w, imported, unmapped)
+	})
+}
+
+// showImportLinkedIn asks for a LinkedIn data export zip and merges it into
+// the loaded values file, asking which side wins where values differ.
+func (e *TextEditor) showImportLinkedIn(w fyne.Window) {
+	showImportFile(w, ".zip", config.ImportLinkedIn, func(imported config.Resume, unmapped []string) {
+		e.mergeImport(w, imported, unmapped)
+	})
+}
+
+// showImportFile asks for a file with extension ext, converts it with
+// importer and hands the result to apply.
+func showImportFile(w fyne.Window, ext string, importer func([]byte) (config.Resume, []string, error), apply func(config.Resume, []string)) {
+	dlg := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if rc == nil {
+			return
+		}
+		defer rc.Close()
+		b, err := io.ReadAll(rc)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		imported, unmapped, err := importer(b)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %w", rc.URI().Name(), err), w)
+			return
+		}
+		apply(imported, unmapped)
+	}, w)
+	dlg.SetFilter(storage.NewExtensionFileFilter([]string{ext}))
+	dlg.Show()
+}
+
+// applyImport writes imported into the loaded values file, or a new one, after
+// confirming that existing resume data may be replaced.
+func (e *TextEditor) applyImport(w fyne.Window, imported config.Resume, unmapped []string) {
+	cfg, ok := e.importTarget(w, imported, unmapped)
+	if !ok {
+		return
+	}
+	msg := fmt.Sprintf("Replace the resume data in %s with the imported resume?\nThe company, role and custom variables are kept.", cfg.Path())
+	dialog.ShowConfirm("Import Resume", msg, func(ok bool) {
+		if !ok {
+			return
+		}
+		cfg.Resume = config.ImportInto(cfg.Resume, imported)
+		if err := cfg.Save(); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving resume: %w", err), w)
+			return
+		}
+		showImported(w, cfg.Path(), unmapped)
+	}, w)
+}
+
+// mergeImport merges imported into the loaded values file, or writes a new
+// one. Values that differ are listed with a checkbox to take the imported one.
+func (e *TextEditor) mergeImport(w fyne.Window, imported config.Resume, unmapped []string) {
+	cfg, ok := e.importTarget(w, imported, unmapped)
+	if !ok {
+		return
+	}
+	save := func(take func(config.Conflict) bool) {
+		cfg.Resume = config.MergeResumes(cfg.Resume, imported, take)
+		if err := cfg.Save(); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving resume: %w", err), w)
+			return
+		}
+		showImported(w, cfg.Path(), unmapped)
+	}
+
+	conflicts := config.MergeConflicts(cfg.Resume, imported)
+	if len(conflicts) == 0 {
+		save(nil)
+		return
+	}
+	take := map[string]bool{}
+	rows := container.NewVBox()
+	for _, c := range conflicts {
+		c := c
+		check := widget.NewCheck(fmt.Sprintf("Use imported %q", c.Incoming), func(on bool) { take[c.Path] = on })
+		rows.Add(widget.NewLabelWithStyle(c.Path, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		rows.Add(widget.NewLabel("Current: " + c.Current))
+		rows.Add(check)
+	}
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(500, 300))
+	dlg := dialog.NewCustomConfirm("Resolve Import Conflicts", "Merge", "Cancel", scroll, func(ok bool) {
+		if ok {
+			save(func(c config.Conflict) bool { return take[c.Path] })
+		}
+	}, w)
+	dlg.Show()
+}
+
+// importTarget returns the loaded config to import into. When there is no
+// values file yet, imported is saved as a new one and ok is false.
+func (e *TextEditor) importTarget(w fyne.Window, imported config.Resume, unmapped []string) (cfg *config.Config, ok bool) {
+	cfg, err := e.loadConfig()
+	if errors.Is(err, fs.ErrNotExist) {
+		cfg = &config.Config{Resume: imported}
+		if err := cfg.CreateConfig(); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving resume: %w", err), w)
+			return nil, false
+		}
+		e.cfg = cfg
+		showImported(w, cfg.Path(), unmapped)
+		return nil, false
+	}
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading config: %w", err), w)
+		return nil, false
+	}
+	return cfg, true
+}
+
+// showImported reports where the import went and which fields were left behind.
+func showImported(w fyne.Window, path string, unmapped []string) {
+	msg := "Imported into " + path
+	if len(unmapped) > 0 {
+		msg += "\n\nThese fields could not be imported:\n" + strings.Join(unmapped, "\n")
+	}
+	dialog.ShowInformation("Import Resume", msg, w)
+}
@@ -5,6 +5,7 @@ import (
     "covlet/pkg/internal"
     "fmt"
     "path/filepath"
+    "strings"
 
     "fyne.io/fyne/v2"
     "fyne.io/fyne/v2/dialog"
@@ -15,12 +16,21 @@ import (
 // and provides methods for building menus and toolbars. This will avoid passing closures
 // around and make testing easier.
 
+// exportDefaults pre-fill the PDF export from the template's front matter.
+type exportDefaults struct {
+    // Title is the document title; the file is named after it unless FileName is set.
+    Title string
+    // FileName is the output file name; its extension is replaced by .pdf.
+    FileName string
+}
+
 // renderMenu builds the menu for the render window. getText returns the latest rendered text.
-func renderMenu(w fyne.Window, getText func() string) *fyne.MainMenu {
+func renderMenu(w fyne.Window, getText func() string, defaults exportDefaults) *fyne.MainMenu {
     // Export as PDF
     exportPDF := fyne.NewMenuItem("Export as PDF…", func() {
         titleEntry := widget.NewEntry()
         titleEntry.SetPlaceHolder("Document Title")
+        titleEntry.SetText(defaults.Title)
         dialog.ShowForm("Export as PDF", "Save", "Cancel",
             []*widget.FormItem{{Text: "Title", Widget: titleEntry}}, func(ok bool) {
                 if !ok {
@@ -36,8 +46,11 @@ func renderMenu(w fyne.Window, getText func() string) *fyne.MainMenu {
                     dialog.ShowError(fmt.Errorf("could not prepare output directory: %w", err), w)
                     return
                 }
-                // File name from title
+                // File name from the template, else from the title
                 base := sanitizeFileName(title)
+                if defaults.FileName != "" {
+                    base = sanitizeFileName(strings.TrimSuffix(defaults.FileName, filepath.Ext(defaults.FileName)))
+                }
                 if base == "" {
                     base = "document"
                 }
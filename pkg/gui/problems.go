@@ -0,0 +1,132 @@
+package gui
+
+import (
+	"covlet/pkg/config"
+	"covlet/pkg/internal"
+	"fmt"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// problem is one diagnostic together with the values file it was found in.
+type problem struct {
+	file string
+	diag config.Diagnostic
+}
+
+// validateValues validates the base values file and every active layer.
+func (e *TextEditor) validateValues() ([]problem, error) {
+	base, err := e.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	files := []string{base.Path()}
+	for _, name := range e.layers {
+		files = append(files, filepath.Join(config.ValuesDir(), filepath.FromSlash(name)))
+	}
+	var out []problem
+	for i, f := range files {
+		// layers are partial, so only the base must have the required fields
+		validate := config.ValidateLayerFile
+		if i == 0 {
+			validate = config.ValidateFile
+		}
+		diags, err := validate(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range diags {
+			out = append(out, problem{file: config.LayerName(f), diag: d})
+		}
+	}
+	return out, nil
+}
+
+// strictProblems turns the references a strict render could not resolve
+// into problems located in their template files.
+func strictProblems(serr *internal.StrictError) []problem {
+	out := make([]problem, len(serr.Unresolved))
+	for i, u := range serr.Unresolved {
+		out[i] = problem{file: u.File, diag: config.Diagnostic{
+			Severity: config.SeverityError,
+			Path:     u.Ref,
+			Line:     u.Line,
+			Column:   u.Col,
+			Message:  u.Reason,
+		}}
+	}
+	return out
+}
+
+// checkValues validates the values files and shows the problems panel, or a
+// short confirmation when there is nothing to report.
+func (e *TextEditor) checkValues(w fyne.Window) {
+	problems, err := e.validateValues()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error validating values: %w", err), w)
+		return
+	}
+	if len(problems) == 0 {
+		dialog.ShowInformation("Validate Values", "No problems found.", w)
+		return
+	}
+	e.showProblems(problems)
+}
+
+// showProblems opens the problems panel, replacing any panel already open.
+func (e *TextEditor) showProblems(problems []problem) {
+	if e.problemsWin != nil {
+		e.problemsWin.Close()
+	}
+	pw := fyne.CurrentApp().NewWindow("Problems")
+	e.problemsWin = pw
+	pw.SetOnClosed(func() {
+		if e.problemsWin == pw {
+			e.problemsWin = nil
+		}
+	})
+
+	errs, warns := 0, 0
+	for _, p := range problems {
+		if p.diag.Severity == config.SeverityError {
+			errs++
+		} else {
+			warns++
+		}
+	}
+	list := widget.NewList(
+		func() int { return len(problems) },
+		func() fyne.CanvasObject {
+			return container.NewHBox(widget.NewIcon(theme.ErrorIcon()), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			p := problems[id]
+			c := o.(*fyne.Container)
+			icon := theme.ErrorIcon()
+			if p.diag.Severity == config.SeverityWarning {
+				icon = theme.WarningIcon()
+			}
+			c.Objects[0].(*widget.Icon).SetResource(icon)
+			c.Objects[1].(*widget.Label).SetText(fmt.Sprintf("%s:%s", p.file, p.diag))
+		},
+	)
+	summary := widget.NewLabel(fmt.Sprintf("%d error(s), %d warning(s)", errs, warns))
+	pw.SetContent(container.NewBorder(summary, nil, nil, nil, list))
+	pw.Resize(fyne.NewSize(800, 300))
+	pw.Show()
+}
+
+// hasErrorProblems reports whether any problem is an error.
+func hasErrorProblems(problems []problem) bool {
+	for _, p := range problems {
+		if p.diag.Severity == config.SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package gui
+
+import (
+	"covlet/pkg/config"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// profileMenu builds the Profile menu: a switcher listing the default resume
+// and every named profile, plus actions to manage them. onChange is called
+// after the profiles or the active profile change so the menu can be rebuilt.
+func (e *TextEditor) profileMenu(w fyne.Window, onChange func()) *fyne.Menu {
+	active := config.ActiveProfile()
+	switchTo := func(name string) {
+		if err := config.RememberProfile(name); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		// the next render or resume edit loads the new profile
+		e.cfg = nil
+		onChange()
+	}
+
+	def := fyne.NewMenuItem("Default", func() { switchTo("") })
+	def.Checked = active == ""
+	items := []*fyne.MenuItem{def}
+	names, err := config.ListProfiles()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error listing profiles: %w", err), w)
+	}
+	for _, name := range names {
+		name := name
+		item := fyne.NewMenuItem(name, func() { switchTo(name) })
+		item.Checked = name == active
+		items = append(items, item)
+	}
+
+	newItem := fyne.NewMenuItem("New Profile…", func() {
+		promptProfileName(w, "New Profile", "", func(name string) {
+			// a new persona starts with the current contact details
+			var r config.Resume
+			cur, err := e.loadConfig()
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				dialog.ShowError(err, w)
+				return
+			}
+			if cur != nil {
+				r = config.ContactOnly(cur.Resume)
+			}
+			if _, err := config.CreateProfile(name, r); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			switchTo(name)
+		})
+	})
+	cloneItem := fyne.NewMenuItem("Clone Current Profile…", func() {
+		promptProfileName(w, "Clone Profile", "", func(name string) {
+			if err := config.CloneProfile(active, name); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			switchTo(name)
+		})
+	})
+	renameItem := fyne.NewMenuItem("Rename Current Profile…", func() {
+		promptProfileName(w, "Rename Profile", active, func(name string) {
+			if err := config.RenameProfile(active, name); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			e.cfg = nil
+			onChange()
+		})
+	})
+	deleteItem := fyne.NewMenuItem("Delete Current Profile…", func() {
+		dialog.ShowConfirm("Delete Profile", fmt.Sprintf("Delete profile %q? This cannot be undone.", active), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := config.DeleteProfile(active); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			e.cfg = nil
+			onChange()
+		}, w)
+	})
+	if active == "" {
+		renameItem.Disabled = true
+		deleteItem.Disabled = true
+	}
+
+	items = append(items, fyne.NewMenuItemSeparator(), newItem, cloneItem, renameItem, deleteItem)
+	return fyne.NewMenu("Profile", items...)
+}
+
+// promptProfileName asks for a profile name and calls onName with the trimmed result.
+func promptProfileName(w fyne.Window, title, initial string, onName func(string)) {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("backend")
+	entry.SetText(initial)
+	dialog.ShowForm(title, "OK", "Cancel",
+		[]*widget.FormItem{{Text: "Profile name", Widget: entry}},
+		func(ok bool) {
+			name := strings.TrimSpace(entry.Text)
+			if !ok || name == "" {
+				return
+			}
+			onName(name)
+		}, w)
+}
@@ -0,0 +1,198 @@
+package gui
+
+import (
+	"covlet/pkg/config"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showResumeEditor opens a window for editing the nested sections of the loaded
+// resume. Edits apply to the editor's config immediately, so the next render
+// picks them up; "Save" persists them through the config package.
+func (e *TextEditor) showResumeEditor(w fyne.Window) {
+	cfg, err := e.loadConfig()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading config: %w", err), w)
+		return
+	}
+	r := &cfg.Resume
+
+	rw := fyne.CurrentApp().NewWindow("Resume")
+	status := widget.NewLabel("")
+	changed := func() { status.SetText("Unsaved changes") }
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Education", newListTab(&r.Education,
+			func(ed config.Education) string { return entryLabel(ed.Degree, ed.Institution) },
+			func(ed *config.Education, onChange func()) fyne.CanvasObject {
+				return widget.NewForm(
+					boundEntry("Institution", &ed.Institution, onChange),
+					boundEntry("Degree", &ed.Degree, onChange),
+					boundDateEntry("Start date", &ed.StartDate, onChange),
+					boundDateEntry("End date", &ed.EndDate, onChange),
+					boundEntry("GPA", &ed.GPA, onChange),
+				)
+			}, changed)),
+		container.NewTabItem("Experience", newListTab(&r.Experience,
+			func(ex config.Experience) string { return entryLabel(ex.Position, ex.Company) },
+			func(ex *config.Experience, onChange func()) fyne.CanvasObject {
+				resp := widget.NewMultiLineEntry()
+				resp.SetPlaceHolder("One responsibility per line")
+				resp.SetMinRowsVisible(6)
+				resp.SetText(strings.Join(ex.Responsibilities, "\n"))
+				resp.OnChanged = func(s string) {
+					ex.Responsibilities = config.SplitLines(s)
+					onChange()
+				}
+				return widget.NewForm(
+					boundEntry("Company", &ex.Company, onChange),
+					boundEntry("Position", &ex.Position, onChange),
+					boundDateEntry("Start date", &ex.StartDate, onChange),
+					boundDateEntry("End date", &ex.EndDate, onChange),
+					widget.NewFormItem("Responsibilities", resp),
+					boundListEntry("Tags", &ex.Tags, onChange),
+				)
+			}, changed)),
+		container.NewTabItem("Projects", newListTab(&r.Projects,
+			func(p config.Project) string { return entryLabel(p.Name, "") },
+			func(p *config.Project, onChange func()) fyne.CanvasObject {
+				return widget.NewForm(
+					boundEntry("Name", &p.Name, onChange),
+					boundEntry("Description", &p.Description, onChange),
+					boundEntry("URL", &p.URL, onChange),
+					boundListEntry("Tags", &p.Tags, onChange),
+				)
+			}, changed)),
+		container.NewTabItem("Skills", newListTab(&r.Skills,
+			func(s string) string { return entryLabel(s, "") },
+			func(s *string, onChange func()) fyne.CanvasObject {
+				return widget.NewForm(boundEntry("Skill", s, onChange))
+			}, changed)),
+	)
+
+	save := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		if err := cfg.Save(); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving resume: %w", err), rw)
+			return
+		}
+		status.SetText("Saved to " + cfg.Path())
+	})
+	footer := container.NewHBox(status, layout.NewSpacer(), save)
+	rw.SetContent(container.NewBorder(nil, footer, nil, nil, tabs))
+	rw.Resize(fyne.NewSize(800, 500))
+	rw.Show()
+}
+
+// newListTab builds a master/detail editor for a slice of resume entries with
+// add, remove and reorder controls. label names an entry in the list and form
+// builds the detail view for the selected entry.
+func newListTab[T any](items *[]T, label func(T) string, form func(item *T, onChange func()) fyne.CanvasObject, onChange func()) fyne.CanvasObject {
+	selected := -1
+	detail := container.NewStack()
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(*items) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(label((*items)[id]))
+		},
+	)
+	showDetail := func() {
+		if selected < 0 || selected >= len(*items) {
+			detail.Objects = []fyne.CanvasObject{widget.NewLabel("Select or add an entry")}
+		} else {
+			id := selected
+			detail.Objects = []fyne.CanvasObject{container.NewVScroll(form(&(*items)[id], func() {
+				list.RefreshItem(id)
+				onChange()
+			}))}
+		}
+		detail.Refresh()
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		showDetail()
+	}
+	reselect := func(id int) {
+		list.Refresh()
+		list.UnselectAll()
+		selected = -1
+		if id >= 0 && id < len(*items) {
+			list.Select(id)
+		} else {
+			showDetail()
+		}
+		onChange()
+	}
+
+	var zero T
+	add := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		*items = append(*items, zero)
+		reselect(len(*items) - 1)
+	})
+	remove := widget.NewButtonWithIcon("", theme.ContentRemoveIcon(), func() {
+		if selected < 0 {
+			return
+		}
+		*items = config.RemoveAt(*items, selected)
+		reselect(-1)
+	})
+	up := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+		if selected <= 0 {
+			return
+		}
+		to := selected - 1
+		*items = moveItem(*items, selected, to)
+		reselect(to)
+	})
+	down := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+		if selected < 0 || selected >= len(*items)-1 {
+			return
+		}
+		to := selected + 1
+		*items = moveItem(*items, selected, to)
+		reselect(to)
+	})
+
+	showDetail()
+	controls := container.NewHBox(add, remove, up, down)
+	split := container.NewHSplit(container.NewBorder(nil, controls, nil, nil, list), detail)
+	split.Offset = 0.35
+	return split
+}
+
+// entryLabel joins the non-empty parts of a list entry's display name.
+func entryLabel(primary, secondary string) string {
+	primary, secondary = strings.TrimSpace(primary), strings.TrimSpace(secondary)
+	switch {
+	case primary == "" && secondary == "":
+		return "(untitled)"
+	case secondary == "":
+		return primary
+	case primary == "":
+		return secondary
+	}
+	return primary + " — " + secondary
+}
+
+// moveItem moves the element at index from to index to, shifting the elements in between.
+func moveItem[T any](s []T, from, to int) []T {
+	if from < 0 || from >= len(s) || to < 0 || to >= len(s) || from == to {
+		return s
+	}
+	item := s[from]
+	if from < to {
+		copy(s[from:to], s[from+1:to+1])
+	} else {
+		copy(s[to+1:from+1], s[to:from])
+	}
+	s[to] = item
+	return s
+}
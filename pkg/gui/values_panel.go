@@ -0,0 +1,103 @@
+package gui
+
+import (
+	"covlet/pkg/config"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showValuesLayers opens a window for choosing which values files are layered
+// on top of the base resume, and shows which layer each final value came from.
+func (e *TextEditor) showValuesLayers(w fyne.Window) {
+	files, err := config.ListValuesFiles()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error listing values files: %w", err), w)
+		return
+	}
+
+	lw := fyne.CurrentApp().NewWindow("Values Layers")
+	var stack *config.Stack
+	var paths []string
+	status := widget.NewLabel("")
+	baseLabel := widget.NewLabel("")
+	sources := widget.NewList(
+		func() int { return len(paths) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			p := paths[id]
+			o.(*widget.Label).SetText(fmt.Sprintf("%s = %v   [%s]", p, stack.Value(p), stack.Sources[p]))
+		},
+	)
+	refresh := func() {
+		base, err := e.loadConfig()
+		if err == nil {
+			baseLabel.SetText("Base: " + config.LayerName(base.Path()))
+			stack, err = e.valuesStack(base)
+		}
+		if err == nil {
+			stack, err = stack.WithOverrides(e.activeOverrides())
+		}
+		if err != nil {
+			status.SetText(err.Error())
+			paths = nil
+		} else {
+			status.SetText(fmt.Sprintf("%d layer(s) active", len(e.layers)+1))
+			paths = stack.Paths()
+		}
+		sources.Refresh()
+	}
+
+	checks := widget.NewCheckGroup(files, func(selected []string) {
+		e.layers = orderedLayers(files, selected)
+		refresh()
+	})
+	checks.Selected = orderedLayers(files, e.layers)
+	left := container.NewBorder(
+		container.NewVBox(widget.NewLabelWithStyle("Layers (applied top to bottom)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), baseLabel),
+		nil, nil, nil, container.NewVScroll(checks))
+	right := container.NewBorder(
+		widget.NewLabelWithStyle("Final values", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		status, nil, nil, sources)
+	split := container.NewHSplit(left, right)
+	split.Offset = 0.3
+
+	refresh()
+	lw.SetContent(split)
+	lw.Resize(fyne.NewSize(900, 500))
+	lw.Show()
+}
+
+// valuesStack merges the base config with the active values layers.
+func (e *TextEditor) valuesStack(base *config.Config) (*config.Stack, error) {
+	layers := []config.Layer{{Name: config.LayerName(base.Path()), Config: base}}
+	for _, name := range e.layers {
+		l, err := config.LoadLayer(filepath.Join(config.ValuesDir(), filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, l)
+	}
+	return config.MergeLayers(layers...)
+}
+
+// orderedLayers keeps the selected layers that still exist, in file list order.
+func orderedLayers(files, selected []string) []string {
+	pos := make(map[string]int, len(files))
+	for i, f := range files {
+		pos[f] = i
+	}
+	var out []string
+	for _, s := range selected {
+		if _, ok := pos[s]; ok {
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return pos[out[i]] < pos[out[j]] })
+	return out
+}
@@ -0,0 +1,208 @@
+package gui
+
+import (
+	"covlet/pkg/config"
+	"covlet/pkg/setup"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showSetupWizard opens the first-run wizard in its own window. onDone is called
+// with the saved config once the resume values file has been written.
+func showSetupWizard(onDone func(*config.Config)) {
+	wz := setup.New()
+	w := fyne.CurrentApp().NewWindow("Covlet Setup")
+	w.Resize(fyne.NewSize(600, 500))
+
+	var show func()
+	show = func() {
+		step := wz.Step()
+		header := widget.NewLabelWithStyle(
+			fmt.Sprintf("Step %d of %d: %s", wz.Index()+1, len(wz.Steps()), step.Title()),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+		back := widget.NewButton("Back", func() {
+			wz.Back()
+			show()
+		})
+		if wz.IsFirst() {
+			back.Disable()
+		}
+		next := widget.NewButton("Next", func() {
+			if err := wz.Next(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			show()
+		})
+		finish := widget.NewButton("Finish", func() {
+			cfg, err := wz.Finish()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			w.Close()
+			if onDone != nil {
+				onDone(cfg)
+			}
+		})
+		finish.Importance = widget.HighImportance
+		if wz.IsLast() {
+			next.Hide()
+		} else {
+			finish.Hide()
+		}
+		nav := container.NewHBox(back, layout.NewSpacer(), next, finish)
+		w.SetContent(container.NewBorder(header, nav, nil, nil, container.NewVScroll(wizardStepContent(wz, show))))
+	}
+	show()
+	w.Show()
+}
+
+// wizardStepContent builds the input widgets for the wizard's current step.
+// refresh redraws the step, e.g. after an entry has been added.
+func wizardStepContent(wz *setup.Wizard, refresh func()) fyne.CanvasObject {
+	r := &wz.Resume
+	switch wz.Step() {
+	case setup.StepContact:
+		return widget.NewForm(
+			boundEntry("Name", &r.Name, nil),
+			boundEntry("Email", &r.Email, nil),
+			boundEntry("Phone", &r.Phone, nil),
+			boundEntry("Address", &r.Address, nil),
+			boundEntry("Website", &r.Website, nil),
+			boundEntry("GitHub", &r.Github, nil),
+		)
+	case setup.StepEducation:
+		ed := &wz.PendingEducation
+		form := widget.NewForm(
+			boundEntry("Institution", &ed.Institution, nil),
+			boundEntry("Degree", &ed.Degree, nil),
+			boundDateEntry("Start date", &ed.StartDate, nil),
+			boundDateEntry("End date", &ed.EndDate, nil),
+			boundEntry("GPA", &ed.GPA, nil),
+		)
+		var added []string
+		for _, e := range r.Education {
+			added = append(added, strings.TrimSpace(e.Degree+" — "+e.Institution))
+		}
+		return wizardListStep(added, form, "Add Education", wz.AddPending, wz.RemoveEducation, refresh)
+	case setup.StepExperience:
+		ex := &wz.PendingExperience
+		resp := widget.NewMultiLineEntry()
+		resp.SetPlaceHolder("One responsibility per line")
+		resp.SetText(strings.Join(ex.Responsibilities, "\n"))
+		resp.OnChanged = func(s string) { ex.Responsibilities = config.SplitLines(s) }
+		form := widget.NewForm(
+			boundEntry("Company", &ex.Company, nil),
+			boundEntry("Position", &ex.Position, nil),
+			boundDateEntry("Start date", &ex.StartDate, nil),
+			boundDateEntry("End date", &ex.EndDate, nil),
+			widget.NewFormItem("Responsibilities", resp),
+		)
+		var added []string
+		for _, e := range r.Experience {
+			added = append(added, e.Position+" at "+e.Company)
+		}
+		return wizardListStep(added, form, "Add Experience", wz.AddPending, wz.RemoveExperience, refresh)
+	case setup.StepSkills:
+		skills := widget.NewMultiLineEntry()
+		skills.SetPlaceHolder("Go, Python, Docker")
+		skills.SetText(strings.Join(r.Skills, ", "))
+		skills.OnChanged = wz.SetSkills
+		return container.NewVBox(widget.NewLabel("Comma or newline separated"), skills)
+	case setup.StepProjects:
+		p := &wz.PendingProject
+		form := widget.NewForm(
+			boundEntry("Name", &p.Name, nil),
+			boundEntry("Description", &p.Description, nil),
+			boundEntry("URL", &p.URL, nil),
+		)
+		var added []string
+		for _, e := range r.Projects {
+			added = append(added, e.Name)
+		}
+		return wizardListStep(added, form, "Add Project", wz.AddPending, wz.RemoveProject, refresh)
+	}
+	return widget.NewLabel("")
+}
+
+// wizardListStep lays out the entries added so far, each with a remove
+// button, above a form for a new one. onAdd reports why the form's entry
+// cannot be added, e.g. a missing company; the problem is shown below the
+// form. refresh redraws the step after an entry was added or removed.
+func wizardListStep(added []string, form *widget.Form, addLabel string, onAdd func() error, onRemove func(int), refresh func()) fyne.CanvasObject {
+	list := container.NewVBox()
+	if len(added) == 0 {
+		list.Add(widget.NewLabel("No entries yet (optional)"))
+	}
+	for i, a := range added {
+		remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			onRemove(i)
+			refresh()
+		})
+		remove.Importance = widget.LowImportance
+		list.Add(container.NewBorder(nil, nil, nil, remove, widget.NewLabel("• "+a)))
+	}
+	problem := widget.NewLabel("")
+	problem.Importance = widget.DangerImportance
+	problem.Hide()
+	add := widget.NewButton(addLabel, func() {
+		if err := onAdd(); err != nil {
+			problem.SetText(err.Error())
+			problem.Show()
+			return
+		}
+		refresh()
+	})
+	return container.NewVBox(list, widget.NewSeparator(), form, problem, add)
+}
+
+// boundEntry returns a form item whose entry writes through to target.
+// onChange, if not nil, is called after every edit.
+func boundEntry(label string, target *string, onChange func()) *widget.FormItem {
+	entry := widget.NewEntry()
+	entry.SetText(*target)
+	entry.OnChanged = func(s string) {
+		*target = s
+		if onChange != nil {
+			onChange()
+		}
+	}
+	return widget.NewFormItem(label, entry)
+}
+
+// boundListEntry is boundEntry for a comma separated list such as tags.
+func boundListEntry(label string, target *[]string, onChange func()) *widget.FormItem {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("comma separated, e.g. backend, go")
+	entry.SetText(strings.Join(*target, ", "))
+	entry.OnChanged = func(s string) {
+		*target = config.SplitList(s)
+		if onChange != nil {
+			onChange()
+		}
+	}
+	return widget.NewFormItem(label, entry)
+}
+
+// boundDateEntry is boundEntry for resume dates; the text is kept as typed.
+func boundDateEntry(label string, target *config.Date, onChange func()) *widget.FormItem {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("e.g. June 2022 or Present")
+	entry.SetText(target.String())
+	entry.OnChanged = func(s string) {
+		*target = config.NewDate(s)
+		if onChange != nil {
+			onChange()
+		}
+	}
+	return widget.NewFormItem(label, entry)
+}
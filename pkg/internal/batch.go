@@ -0,0 +1,346 @@
+package internal
+
+import (
+	"bytes"
+	"covlet/pkg/config"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Job is one application in a batch: overrides applied to the base resume.
+type Job struct {
+	// Row is the 1-based data row in the jobs file (the CSV header is not counted).
+	Row       int
+	Overrides map[string]string
+}
+
+// Label names the job in reports, e.g. "row 3 (ACME)".
+func (j Job) Label() string {
+	if c := j.Overrides["CompanyToApplyTo"]; c != "" {
+		return fmt.Sprintf("row %d (%s)", j.Row, c)
+	}
+	return fmt.Sprintf("row %d", j.Row)
+}
+
+// jobAliases maps the short column names of a jobs file to override paths.
+var jobAliases = map[string]string{
+	"company":  "CompanyToApplyTo",
+	"role":     "RoleToApplyTo",
+	"position": "RoleToApplyTo",
+	"manager":  "HiringManager",
+}
+
+// ReadJobsFile reads a jobs file: a CSV file with a header row, or a YAML
+// (or JSON) list of mappings. Column names and keys are override paths as
+// accepted by config.ApplyOverrides ("Name", "Experience.0.Company", or any
+// custom variable), plus the shortcuts company, role (or position) and
+// manager. Empty cells are skipped so the base resume value is kept.
+func ReadJobsFile(path string) ([]Job, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ReadJobsCSV(b)
+	case ".yml", ".yaml", ".json":
+		return ReadJobsYAML(b)
+	}
+	return nil, fmt.Errorf("%s: jobs file must be .csv, .yml, .yaml or .json", path)
+}
+
+// ReadJobsCSV reads jobs from CSV data with a header row; see ReadJobsFile.
+func ReadJobsCSV(data []byte) ([]Job, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("jobs file is empty")
+	}
+	header := records[0]
+	var jobs []Job
+	for i, rec := range records[1:] {
+		job := Job{Row: i + 1, Overrides: map[string]string{}}
+		for c, v := range rec {
+			if c >= len(header) {
+				return nil, fmt.Errorf("row %d: more cells than header columns", job.Row)
+			}
+			if v = strings.TrimSpace(v); v != "" {
+				job.Overrides[jobKey(header[c])] = v
+			}
+		}
+		if len(job.Overrides) > 0 {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// ReadJobsYAML reads jobs from a YAML or JSON list of mappings; see
+// ReadJobsFile. Nested mappings become dotted paths and lists of scalars
+// comma separated values. Rows without any value are skipped, as in CSV.
+func ReadJobsYAML(data []byte) ([]Job, error) {
+	var rows []map[string]any
+	if err := yaml.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("jobs file must be a list of mappings: %w", err)
+	}
+	var jobs []Job
+	for i, row := range rows {
+		job := Job{Row: i + 1, Overrides: map[string]string{}}
+		if err := flattenJob(job.Overrides, "", row); err != nil {
+			return nil, fmt.Errorf("row %d: %w", job.Row, err)
+		}
+		if len(job.Overrides) > 0 {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func flattenJob(out map[string]string, prefix string, v any) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			key := k
+			if prefix == "" {
+				key = jobKey(k)
+			} else {
+				key = prefix + "." + k
+			}
+			if err := flattenJob(out, key, item); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if len(v) == 0 {
+			return nil
+		}
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]any, []any:
+				return fmt.Errorf("%s: lists of mappings are not supported; use indexed keys such as %s.0.company", prefix, prefix)
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+	default:
+		if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+			out[prefix] = s
+		}
+	}
+	return nil
+}
+
+func jobKey(k string) string {
+	k = strings.TrimSpace(k)
+	if alias, ok := jobAliases[strings.ToLower(k)]; ok {
+		return alias
+	}
+	return k
+}
+
+// Batch renders one letter per job.
+type Batch struct {
+	// Template is executed with each job's data.
+	Template *template.Template
+	// Base is the resume every job's overrides are applied to.
+	Base config.Resume
+	// OutDir receives the rendered files.
+	OutDir string
+	// Name is a text/template for the output file name, executed with the
+	// job's data; it may use a .Row variable. Its extension picks the output
+	// format (see FormatOf) unless Format is set. Unsafe characters are
+	// replaced. When empty, the template's output name is used, or
+	// DefaultBatchName.
+	Name string
+	// Format overrides the format picked from the file name.
+	Format Format
+	// Title is a text/template for the PDF heading and HTML title; optional.
+	// When empty, the template's title is used.
+	Title string
+	// Meta is the template's front matter; its defaults and required
+	// variables apply to every job.
+	Meta FrontMatter
+	// Parallel is the number of letters rendered at once; 0 means one per CPU.
+	Parallel int
+}
+
+// DefaultBatchName is the file name pattern used when Batch.Name is empty.
+const DefaultBatchName = "{{ .Row }}-{{ .CompanyToApplyTo }}-{{ .RoleToApplyTo }}.txt"
+
+// BatchResult is the outcome of one job.
+type BatchResult struct {
+	Job  Job
+	Path string
+	Err  error
+}
+
+// Run renders every job and returns one result per job, in job order. A
+// failing job does not stop the others. Output names that collide get a
+// numeric suffix.
+func (b *Batch) Run(jobs []Job) ([]BatchResult, error) {
+	pattern := b.Name
+	if pattern == "" {
+		pattern = b.Meta.Output
+	}
+	if pattern == "" {
+		pattern = DefaultBatchName
+	}
+	title := b.Title
+	if title == "" {
+		title = b.Meta.TitlePattern()
+	}
+	nameTpl, err := NewTemplate("name").Option("missingkey=zero").Parse(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file name pattern: %w", err)
+	}
+	var titleTpl *template.Template
+	if title != "" {
+		if titleTpl, err = NewTemplate("title").Option("missingkey=zero").Parse(title); err != nil {
+			return nil, fmt.Errorf("invalid title pattern: %w", err)
+		}
+	}
+	if err := os.MkdirAll(b.OutDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	// resolve data and names up front so collisions get stable suffixes
+	results := make([]BatchResult, len(jobs))
+	data := make([]map[string]any, len(jobs))
+	used := map[string]bool{}
+	for i, job := range jobs {
+		results[i].Job = job
+		r, err := config.ApplyOverrides(b.Base, job.Overrides)
+		if err == nil {
+			r, err = b.Meta.Apply(r)
+		}
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		data[i] = r.Data()
+		data[i]["Row"] = job.Row
+		name, err := execString(nameTpl, data[i])
+		if err != nil {
+			results[i].Err = fmt.Errorf("file name: %w", err)
+			continue
+		}
+		name = uniqueName(safeFileName(name, job.Row), used)
+		results[i].Path = filepath.Join(b.OutDir, name)
+	}
+
+	parallel := b.Parallel
+	if parallel <= 0 {
+		parallel = runtime.GOMAXPROCS(0)
+	}
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(res *BatchResult, data map[string]any) {
+			defer func() { <-sem; wg.Done() }()
+			res.Err = b.render(res.Path, data, titleTpl)
+		}(&results[i], data[i])
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func (b *Batch) render(path string, data map[string]any, titleTpl *template.Template) error {
+	text, err := RenderEditor(b.Template, data)
+	if err != nil {
+		return err
+	}
+	var title string
+	if titleTpl != nil {
+		if title, err = execString(titleTpl, data); err != nil {
+			return fmt.Errorf("title: %w", err)
+		}
+	}
+	format := b.Format
+	if format == "" {
+		format = FormatOf(path)
+	}
+	var buf bytes.Buffer
+	if err := WriteOutput(&buf, text, format, title); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// execString executes a name or title pattern; missing variables are empty.
+func execString(t *template.Template, data any) (string, error) {
+	out, err := RenderEditor(t, data)
+	return strings.TrimSpace(strings.ReplaceAll(string(out), "<no value>", "")), err
+}
+
+// safeFileName is cleanFileName with a name for the row when nothing but an
+// extension is left.
+func safeFileName(name string, row int) string {
+	name = cleanFileName(name)
+	if name == "" || strings.HasPrefix(name, ".") {
+		name = "row-" + strconv.Itoa(row) + name
+	}
+	return name
+}
+
+// cleanFileName replaces characters that are not allowed in file names and
+// collapses the dashes left by empty values.
+func cleanFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < ' ' {
+			return -1
+		}
+		return r
+	}, name)
+	for strings.Contains(name, "--") {
+		name = strings.ReplaceAll(name, "--", "-")
+	}
+	name = strings.ReplaceAll(name, "-.", ".")
+	return strings.Trim(name, " -")
+}
+
+func uniqueName(name string, used map[string]bool) string {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	out := name
+	for n := 2; used[strings.ToLower(out)]; n++ {
+		out = fmt.Sprintf("%s-%d%s", stem, n, ext)
+	}
+	used[strings.ToLower(out)] = true
+	return out
+}
+
+// Failed returns the results that have an error, in job order.
+func Failed(results []BatchResult) []BatchResult {
+	var out []BatchResult
+	for _, r := range results {
+		if r.Err != nil {
+			out = append(out, r)
+		}
+	}
+	return out
+}
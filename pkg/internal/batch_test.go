@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"covlet/pkg/config"
+)
+
+func TestReadJobs(t *testing.T) {
+	csvJobs, err := ReadJobsCSV([]byte("\ufeffcompany,Role,manager,PostingURL,Skills\nACME,Engineer,Sam,https://acme.example/jobs/1,\"Go,SQL\"\n,,,,\nInitech,SRE,,,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlJobs, err := ReadJobsYAML([]byte("- company: ACME\n  role: Engineer\n  manager: Sam\n  PostingURL: https://acme.example/jobs/1\n  Skills: [Go, SQL]\n- {}\n- company: \"\"\n  Skills: []\n- company: Initech\n  position: SRE\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// rows count data lines, so the blank rows shift Initech
+	for name, tt := range map[string]struct {
+		jobs  []Job
+		label string
+	}{"csv": {csvJobs, "row 3 (Initech)"}, "yaml": {yamlJobs, "row 4 (Initech)"}} {
+		jobs := tt.jobs
+		if len(jobs) != 2 {
+			t.Fatalf("%s: expected 2 jobs (blank rows skipped), got %+v", name, jobs)
+		}
+		o := jobs[0].Overrides
+		if o["CompanyToApplyTo"] != "ACME" || o["RoleToApplyTo"] != "Engineer" || o["HiringManager"] != "Sam" || o["PostingURL"] == "" || o["Skills"] != "Go,SQL" {
+			t.Fatalf("%s: unexpected overrides %v", name, o)
+		}
+		if jobs[1].Overrides["RoleToApplyTo"] != "SRE" || jobs[1].Label() != tt.label {
+			t.Fatalf("%s: unexpected second job %+v", name, jobs[1])
+		}
+	}
+}
+
+func TestBatchRun(t *testing.T) {
+	tpl, err := NewTemplate("letter").Parse("Dear {{ .HiringManager }}, {{ .Name }} applies to {{ .CompanyToApplyTo }}.{{ if eq .CompanyToApplyTo \"Broken\" }}{{ index .Skills 5 }}{{ end }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	b := &Batch{
+		Template: tpl,
+		Base:     config.Resume{Name: "Jane Doe"},
+		OutDir:   dir,
+		Name:     "{{ .CompanyToApplyTo }}-{{ .RoleToApplyTo }}.txt",
+		Parallel: 2,
+	}
+	jobs := []Job{
+		{Row: 1, Overrides: map[string]string{"CompanyToApplyTo": "ACME", "HiringManager": "Sam"}},
+		{Row: 2, Overrides: map[string]string{"CompanyToApplyTo": "ACME", "HiringManager": "Ann"}},
+		{Row: 3, Overrides: map[string]string{"CompanyToApplyTo": "Broken"}},
+		{Row: 4, Overrides: map[string]string{"CompanyToApplyTo": "A/B", "Skills.7": "Go"}},
+		{Row: 5, Overrides: map[string]string{"CompanyToApplyTo": "Initech", "RoleToApplyTo": "SRE"}},
+	}
+	results, err := b.Run(jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ACME.txt", "ACME-2.txt", "", "", "Initech-SRE.txt"}
+	for i, r := range results {
+		if want[i] == "" {
+			if r.Err == nil {
+				t.Errorf("row %d: expected an error", r.Job.Row)
+			}
+			continue
+		}
+		if r.Err != nil || filepath.Base(r.Path) != want[i] {
+			t.Errorf("row %d: got %s, %v; want %s", r.Job.Row, r.Path, r.Err, want[i])
+		}
+	}
+	if failed := Failed(results); len(failed) != 2 || failed[0].Job.Row != 3 || failed[1].Job.Row != 4 {
+		t.Fatalf("unexpected failures: %+v", failed)
+	}
+	b2, _ := os.ReadFile(filepath.Join(dir, "ACME-2.txt"))
+	if string(b2) != "Dear Ann, Jane Doe applies to ACME." {
+		t.Fatalf("unexpected letter %q", b2)
+	}
+}
+
+func TestSafeFileName(t *testing.T) {
+	tests := map[string]string{
+		"ACME--.txt":       "ACME.txt",
+		"A/B: C?.pdf":      "A_B_ C_.pdf",
+		"-.txt":            "row-7.txt",
+		"  Initech-SRE.md": "Initech-SRE.md",
+	}
+	for in, want := range tests {
+		if got := safeFileName(in, 7); got != want {
+			t.Errorf("safeFileName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
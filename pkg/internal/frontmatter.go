@@ -0,0 +1,306 @@
+package internal
+
+import (
+	"covlet/pkg/config"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrMissingRequired is returned when a variable a template requires is empty.
+var ErrMissingRequired = errors.New("missing required variables")
+
+// FrontMatter is the optional YAML block that opens a .tpl or .tmpl file
+// between two "---" lines and describes the template:
+//
+//	---
+//	title: Formal cover letter
+//	tags: [formal]
+//	output: "{{ .CompanyToApplyTo }}-cover-letter.pdf"
+//	required: [CompanyToApplyTo, HiringManager]
+//	defaults:
+//	  HiringManager: Hiring Manager
+//	---
+type FrontMatter struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Tags        []string `yaml:"tags"`
+	// Output is the default output file name, a template over the values.
+	Output string `yaml:"output"`
+	// PDFTitle is the default PDF heading and HTML title, a template over the
+	// values; Title is used when it is empty.
+	PDFTitle string `yaml:"pdf_title"`
+	// Required lists variables, as override paths, that must not be empty.
+	Required []string `yaml:"required"`
+	// Defaults fills variables, by override path, that are empty.
+	Defaults map[string]any `yaml:"defaults"`
+	// Layout is the layout the template extends, as in the extends directive.
+	Layout string `yaml:"layout"`
+}
+
+// hasFrontMatter reports whether the template file at path may open with
+// front matter: .tpl and .tmpl files and unsaved buffers. Other types such
+// as Markdown keep a leading "---" as text.
+func hasFrontMatter(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tpl", ".tmpl":
+		return true
+	}
+	return path == ""
+}
+
+// ParseFrontMatter splits the front matter off template text. It returns the
+// metadata (zero without front matter) and the text to parse, in which the
+// front matter is replaced by an empty comment spanning the same lines so
+// line numbers in template errors still match the file.
+func ParseFrontMatter(text string) (FrontMatter, string, error) {
+	fm, body, _, err := splitFrontMatter(text)
+	return fm, body, err
+}
+
+// splitFrontMatter is ParseFrontMatter that also returns the text after the
+// front matter as written.
+func splitFrontMatter(text string) (fm FrontMatter, body, rest string, err error) {
+	first, after, ok := strings.Cut(text, "\n")
+	if !ok || strings.TrimRight(first, " \t\r") != "---" {
+		return fm, text, text, nil
+	}
+	var block strings.Builder
+	for {
+		line, next, more := strings.Cut(after, "\n")
+		if t := strings.TrimRight(line, " \t\r"); t == "---" || t == "..." {
+			rest = next
+			break
+		}
+		if !more {
+			return fm, "", "", errors.New("front matter: no closing --- line")
+		}
+		block.WriteString(line + "\n")
+		after = next
+	}
+	dec := yaml.NewDecoder(strings.NewReader(block.String()))
+	dec.KnownFields(true)
+	if err := dec.Decode(&fm); err != nil && !errors.Is(err, io.EOF) {
+		return fm, "", "", fmt.Errorf("front matter: %w", err)
+	}
+	// the comment takes the front matter's line breaks, so the text after it
+	// starts on the same line as in the file; unless that text opens with
+	// space the comment ends a line early and trims its last break, so
+	// columns match too
+	n := strings.Count(text[:len(text)-len(rest)], "\n")
+	if rest != "" && strings.ContainsRune(" \t\r\n", rune(rest[0])) {
+		body = "{{/*" + strings.Repeat("\n", n) + "*/}}" + rest
+	} else {
+		body = "{{/*" + strings.Repeat("\n", n-1) + "*/ -}}\n" + rest
+	}
+	return fm, body, rest, nil
+}
+
+// ReadFrontMatter reads the front matter of the template file at path.
+func ReadFrontMatter(path string) (FrontMatter, error) {
+	if !hasFrontMatter(path) {
+		return FrontMatter{}, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return FrontMatter{}, err
+	}
+	fm, _, err := ParseFrontMatter(string(b))
+	return fm, err
+}
+
+// inherit fills fields of fm that are empty from base, the front matter of a
+// layout fm's template extends. Required variables and defaults are merged,
+// fm's defaults winning.
+func (fm *FrontMatter) inherit(base FrontMatter) {
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&fm.Title, base.Title)
+	fill(&fm.Description, base.Description)
+	fill(&fm.Output, base.Output)
+	fill(&fm.PDFTitle, base.PDFTitle)
+	if len(fm.Tags) == 0 {
+		fm.Tags = base.Tags
+	}
+	for _, r := range base.Required {
+		if !containsFold(fm.Required, r) {
+			fm.Required = append(fm.Required, r)
+		}
+	}
+	for k, v := range base.Defaults {
+		if _, ok := fm.Defaults[k]; !ok {
+			if fm.Defaults == nil {
+				fm.Defaults = map[string]any{}
+			}
+			fm.Defaults[k] = v
+		}
+	}
+}
+
+// Apply fills the empty variables of r that have defaults, then checks that
+// no required variable is empty; the error wraps ErrMissingRequired and
+// names all of them.
+func (fm FrontMatter) Apply(r config.Resume) (config.Resume, error) {
+	if len(fm.Defaults) > 0 {
+		data := r.Data()
+		overrides := map[string]string{}
+		for path, v := range fm.Defaults {
+			if cur, _ := lookupPath(data, path); !isEmpty(cur) {
+				continue
+			}
+			s, err := defaultString(v)
+			if err != nil {
+				return r, fmt.Errorf("front matter default %s: %w", path, err)
+			}
+			overrides[path] = s
+		}
+		var err error
+		if r, err = config.ApplyOverrides(r, overrides); err != nil {
+			return r, fmt.Errorf("front matter defaults: %w", err)
+		}
+	}
+	if missing := fm.Missing(r.Data()); len(missing) > 0 {
+		return r, fmt.Errorf("%w: %s", ErrMissingRequired, strings.Join(missing, ", "))
+	}
+	return r, nil
+}
+
+// Missing returns the required variables that are empty in data.
+func (fm FrontMatter) Missing(data map[string]any) []string {
+	var missing []string
+	for _, path := range fm.Required {
+		if v, _ := lookupPath(data, path); isEmpty(v) {
+			missing = append(missing, path)
+		}
+	}
+	return missing
+}
+
+// OutputName executes the Output pattern with data, replacing characters
+// not allowed in file names; it is empty when the template names no output
+// file.
+func (fm FrontMatter) OutputName(data map[string]any) (string, error) {
+	name, err := fm.pattern("output", fm.Output, data)
+	if err != nil {
+		return "", err
+	}
+	if name = cleanFileName(name); strings.HasPrefix(name, ".") {
+		name = ""
+	}
+	return name, nil
+}
+
+// DocumentTitle executes the PDFTitle pattern, or Title, with data.
+func (fm FrontMatter) DocumentTitle(data map[string]any) (string, error) {
+	if fm.PDFTitle != "" {
+		return fm.pattern("pdf_title", fm.PDFTitle, data)
+	}
+	return fm.pattern("title", fm.Title, data)
+}
+
+// TitlePattern is the PDFTitle pattern, or Title.
+func (fm FrontMatter) TitlePattern() string {
+	if fm.PDFTitle != "" {
+		return fm.PDFTitle
+	}
+	return fm.Title
+}
+
+func (fm FrontMatter) pattern(key, pattern string, data map[string]any) (string, error) {
+	if pattern == "" {
+		return "", nil
+	}
+	t, err := NewTemplate(key).Option("missingkey=zero").Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("front matter %s: %w", key, err)
+	}
+	s, err := execString(t, data)
+	if err != nil {
+		return "", fmt.Errorf("front matter %s: %w", key, err)
+	}
+	return s, nil
+}
+
+// Summary describes the template in one line for file lists: its title (or
+// description) and tags.
+func (fm FrontMatter) Summary() string {
+	s := fm.Title
+	if s == "" {
+		s = fm.Description
+	}
+	for _, t := range fm.Tags {
+		s += " #" + t
+	}
+	return strings.TrimSpace(s)
+}
+
+// defaultString turns a default value into override text; lists become
+// comma separated values.
+func defaultString(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			switch item.(type) {
+			case map[string]any, []any:
+				return "", errors.New("lists may only hold plain values")
+			}
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		return "", errors.New("use a dotted path such as Experience.0.Company instead of a mapping")
+	}
+	return fmt.Sprint(v), nil
+}
+
+// lookupPath resolves an override path such as "HiringManager" or
+// "Experience.0.Company" in template data.
+func lookupPath(data map[string]any, path string) (any, bool) {
+	v := reflect.ValueOf(data)
+	for _, seg := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, false
+			}
+			v = v.Elem()
+		}
+		if i, err := strconv.Atoi(seg); err == nil && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) {
+			if i < 0 || i >= v.Len() {
+				return nil, false
+			}
+			v = v.Index(i)
+			continue
+		}
+		f, ok := fieldOf(v, seg)
+		if !ok {
+			return nil, false
+		}
+		v = f
+	}
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
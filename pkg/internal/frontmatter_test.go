@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"covlet/pkg/config"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const frontMatterLetter = `---
+title: Formal letter
+description: For banks
+tags: [formal, finance]
+output: "{{ .CompanyToApplyTo }}: letter.pdf"
+pdf_title: "Application to {{ .CompanyToApplyTo }}"
+required: [CompanyToApplyTo]
+defaults:
+  HiringManager: Hiring Manager
+  Skills: [Go, SQL]
+---
+Dear {{ .HiringManager }}, {{ .Skills | oxford }}.`
+
+func TestParseFrontMatter(t *testing.T) {
+	fm, body, err := ParseFrontMatter(frontMatterLetter)
+	if err != nil {
+		t.Fatalf("ParseFrontMatter: %v", err)
+	}
+	if fm.Title != "Formal letter" || fm.Description != "For banks" || !reflect.DeepEqual(fm.Tags, []string{"formal", "finance"}) ||
+		!reflect.DeepEqual(fm.Required, []string{"CompanyToApplyTo"}) || fm.Defaults["HiringManager"] != "Hiring Manager" {
+		t.Fatalf("unexpected front matter %+v", fm)
+	}
+	if got, want := strings.Count(body, "\n"), strings.Count(frontMatterLetter, "\n"); got != want {
+		t.Fatalf("body has %d lines, want %d:\n%s", got, want, body)
+	}
+	if fm.Summary() != "Formal letter #formal #finance" {
+		t.Fatalf("Summary = %q", fm.Summary())
+	}
+
+	// without front matter the text is unchanged
+	if fm, body, err := ParseFrontMatter("--- not front matter\n"); err != nil || body != "--- not front matter\n" || fm.Title != "" {
+		t.Fatalf("got %+v, %q, %v", fm, body, err)
+	}
+	for _, bad := range []string{"---\ntitle: x\n", "---\ntitel: x\n---\n", "---\ntags: {a: b}\n---\n"} {
+		if _, _, err := ParseFrontMatter(bad); err == nil || !strings.Contains(err.Error(), "front matter") {
+			t.Errorf("%q: expected a front matter error, got %v", bad, err)
+		}
+	}
+}
+
+func TestRenderer_FrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"layouts/base.tpl": "---\nrequired: [Name]\ndefaults: {RoleToApplyTo: engineer}\ntitle: Base\n---\n{{ block \"body\" . }}{{ end }} as {{ .RoleToApplyTo }}",
+		"letters/notes.md": "---\n{{ .Name }}",
+	})
+	r := &Renderer{TemplatesDir: dir}
+	l, err := r.Load(filepath.Join(dir, "letters", "acme.tpl"), "---\nlayout: layouts/base\noutput: acme.txt\n---\n{{ define \"body\" }}Hi {{ .HiringManager }}{{ end }}")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if l.Meta.Title != "Base" || l.Meta.Output != "acme.txt" || !reflect.DeepEqual(l.Meta.Required, []string{"Name"}) || l.Meta.Defaults["RoleToApplyTo"] != "engineer" {
+		t.Fatalf("expected the layout's metadata to be inherited, got %+v", l.Meta)
+	}
+	if _, err := l.Meta.Apply(config.Resume{}); !errors.Is(err, ErrMissingRequired) || !strings.Contains(err.Error(), "Name") {
+		t.Fatalf("expected Name to be required, got %v", err)
+	}
+	res, err := l.Meta.Apply(config.Resume{Name: "Jane", Custom: map[string]any{"HiringManager": "Sam"}})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	out, err := RenderEditor(l.Template, res.Data())
+	if err != nil || string(out) != "Hi Sam as engineer" {
+		t.Fatalf("got %q, %v", out, err)
+	}
+
+	// Markdown keeps a leading --- as text
+	md, err := r.ParseFile(filepath.Join(dir, "letters", "notes.md"))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if out, _ := RenderEditor(md, map[string]any{"Name": "Jane"}); string(out) != "---\nJane" {
+		t.Fatalf("got %q", out)
+	}
+
+	// errors after the front matter keep their line numbers
+	_, err = r.Load("", "---\ntitle: x\n---\n\n{{ .Name ")
+	if err == nil || !strings.Contains(err.Error(), ":5:") {
+		t.Fatalf("expected the error on line 5, got %v", err)
+	}
+}
+
+func TestFrontMatter_Apply(t *testing.T) {
+	fm, _, err := ParseFrontMatter(frontMatterLetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = fm.Apply(config.Resume{})
+	if !errors.Is(err, ErrMissingRequired) || !strings.HasSuffix(err.Error(), ": CompanyToApplyTo") {
+		t.Fatalf("expected CompanyToApplyTo to be missing, got %v", err)
+	}
+	r, err := fm.Apply(config.Resume{CompanyToApplyTo: "ACME/Corp", Skills: []string{"Rust"}})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	data := r.Data()
+	if data["HiringManager"] != "Hiring Manager" || !reflect.DeepEqual(r.Skills, []string{"Rust"}) {
+		t.Fatalf("expected defaults only for empty values, got %v and %v", data["HiringManager"], r.Skills)
+	}
+	if name, err := fm.OutputName(data); err != nil || name != "ACME_Corp_ letter.pdf" {
+		t.Fatalf("OutputName = %q, %v", name, err)
+	}
+	if title, err := fm.DocumentTitle(data); err != nil || title != "Application to ACME/Corp" {
+		t.Fatalf("DocumentTitle = %q, %v", title, err)
+	}
+	if r, err := (FrontMatter{Defaults: map[string]any{"Skills": []any{"Go", "SQL"}}}).Apply(config.Resume{}); err != nil || !reflect.DeepEqual(r.Skills, []string{"Go", "SQL"}) {
+		t.Fatalf("expected list defaults, got %v, %v", r.Skills, err)
+	}
+}
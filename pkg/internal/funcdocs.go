@@ -0,0 +1,36 @@
+package internal
+
+// FuncDoc describes a template helper for the in-app function reference.
+type FuncDoc struct {
+	Name string
+	// Usage shows the arguments, e.g. "join SEP LIST".
+	Usage string
+	// Summary is one sentence on what the helper does.
+	Summary string
+	// Example is a template snippet using the helper.
+	Example string
+}
+
+// FuncDocs documents every helper in Funcs, grouped as in the reference.
+var FuncDocs = []FuncDoc{
+	{"join", "join SEP LIST", "Joins the items of a list with SEP.", `{{ .Skills | join ", " }}`},
+	{"oxford", "oxford LIST", `Joins a list as prose: "Go, Rust, and SQL".`, `{{ .Skills | oxford }}`},
+	{"first", "first LIST", "The first item of a list, or nothing if it is empty.", `{{ with first .Experience }}{{ .Company }}{{ end }}`},
+	{"last", "last LIST", "The last item of a list, or nothing if it is empty.", `{{ (last .Education).Institution }}`},
+	{"limit", "limit N LIST", "At most the first N items of a list.", `{{ .Skills | limit 3 | oxford }}`},
+	{"sortBy", "sortBy FIELD LIST", `Entries sorted by a field such as "Company" or "start_date"; prefix "-" for descending.`, `{{ range sortBy "-StartDate" .Experience }}…{{ end }}`},
+	{"filterByTag", "filterByTag TAG LIST", "Entries whose tags include TAG.", `{{ range filterByTag "backend" .Experience }}…{{ end }}`},
+	{"upper", "upper TEXT", "Upper-cases text.", `{{ upper .CompanyToApplyTo }}`},
+	{"lower", "lower TEXT", "Lower-cases text.", `{{ lower .RoleToApplyTo }}`},
+	{"title", "title TEXT", "Capitalizes the first letter of every word.", `{{ title .RoleToApplyTo }}`},
+	{"default", "default FALLBACK VALUE", "VALUE, or FALLBACK when VALUE is empty.", `{{ .HiringManager | default "Hiring Manager" }}`},
+	{"pluralize", "pluralize N WORD [PLURAL]", "WORD when N (a number or a list's length) is one, else its plural.", `{{ len .Skills }} {{ pluralize .Skills "skill" }}`},
+	{"truncateWords", "truncateWords N TEXT", "The first N words of text, with … when cut.", `{{ (first .Projects).Description | truncateWords 20 }}`},
+	{"indent", "indent N TEXT", "Indents every non-blank line by N spaces.", `{{ .Address | indent 4 }}`},
+	{"wrap", "wrap WIDTH TEXT", "Reflows each line to at most WIDTH characters.", `{{ (first .Projects).Description | wrap 72 }}`},
+	{"now", "now", "The current date and time, for date.", `{{ now | date "full" }}`},
+	{"date", "date LAYOUT DATE", "Formats now, a resume date or a date string with a Go layout or year, short, long, iso or full.", `{{ now | date "January 2, 2006" }}`},
+	{"formatDate", "formatDate LAYOUT DATE", "Formats a resume date; see date.", `{{ .StartDate | formatDate "short" }}`},
+	{"duration", "duration START END", `The time between two dates, e.g. "2 years 3 months"; an empty end is ongoing.`, `{{ duration .StartDate .EndDate }}`},
+	{"sortExperience", "sortExperience LIST", "Experience in reverse-chronological order.", `{{ range sortExperience .Experience }}…{{ end }}`},
+}
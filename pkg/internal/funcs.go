@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"covlet/pkg/config"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Funcs returns the helper functions registered on every template. Each one
+// is described in FuncDocs. Helpers taking a list or text take it last so
+// they can be piped: {{ .Skills | limit 3 | oxford }}.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		// dates
+		"formatDate":     formatDate,
+		"duration":       duration,
+		"sortExperience": config.SortExperience,
+		"date":           date,
+		"now":            time.Now,
+		// text
+		"upper":         strings.ToUpper,
+		"lower":         strings.ToLower,
+		"title":         title,
+		"default":       defaultValue,
+		"pluralize":     pluralize,
+		"truncateWords": truncateWords,
+		"indent":        indent,
+		"wrap":          wrap,
+		// lists
+		"join":        join,
+		"oxford":      oxford,
+		"first":       first,
+		"last":        last,
+		"limit":       limit,
+		"sortBy":      sortBy,
+		"filterByTag": filterByTag,
+	}
+}
+
+// NewTemplate returns an empty template named name with Funcs registered.
+func NewTemplate(name string) *template.Template {
+	return template.New(name).Funcs(Funcs())
+}
+
+// formatDate formats a resume date with a Go layout or one of the names in
+// config.DateLayouts. The date comes last so it can be piped:
+// {{ .StartDate | formatDate "short" }}.
+func formatDate(layout string, d any) (string, error) {
+	date, err := toDate(d)
+	if err != nil {
+		return "", err
+	}
+	return date.Format(layout), nil
+}
+
+// duration describes the time between two resume dates, e.g. "2 years 3 months".
+func duration(start, end any) (string, error) {
+	s, err := toDate(start)
+	if err != nil {
+		return "", err
+	}
+	e, err := toDate(end)
+	if err != nil {
+		return "", err
+	}
+	return config.Duration(s, e), nil
+}
+
+// date formats a resume date, a time such as now, or a date string with a Go
+// layout or one of the names in config.DateLayouts: {{ now | date "full" }}.
+func date(layout string, v any) (string, error) {
+	switch t := v.(type) {
+	case time.Time:
+		if named, ok := config.DateLayouts[layout]; ok {
+			layout = named
+		}
+		return t.Format(layout), nil
+	case *time.Time:
+		if t == nil {
+			return "", nil
+		}
+		return date(layout, *t)
+	}
+	return formatDate(layout, v)
+}
+
+func toDate(v any) (config.Date, error) {
+	switch d := v.(type) {
+	case config.Date:
+		return d, nil
+	case *config.Date:
+		if d == nil {
+			return config.Date{}, nil
+		}
+		return *d, nil
+	case string:
+		return config.NewDate(d), nil
+	case nil:
+		return config.Date{}, nil
+	}
+	return config.Date{}, fmt.Errorf("expected a date, got %T", v)
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"covlet/pkg/config"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFuncs_DateHelpers(t *testing.T) {
+	src := `{{ range sortExperience .Experience }}{{ .Company }}: {{ .StartDate | formatDate "short" }} – {{ formatDate "long" .EndDate }} ({{ duration .StartDate .EndDate }})
+{{ end }}`
+	tpl, err := NewTemplate("t").Parse(src)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	data := map[string]any{"Experience": []config.Experience{
+		{Company: "Test Inc.", StartDate: config.NewDate("May 2021"), EndDate: config.NewDate("August 2021")},
+		{Company: "Go Corp", StartDate: config.NewDate("2022-06"), EndDate: config.NewDate("2023-09")},
+	}}
+	out, err := RenderEditor(tpl, data)
+	if err != nil {
+		t.Fatalf("RenderEditor: %v", err)
+	}
+	want := "Go Corp: Jun 2022 – September 2023 (1 year 3 months)\nTest Inc.: May 2021 – August 2021 (3 months)\n"
+	if string(out) != want {
+		t.Fatalf("unexpected output:\n%q\nwant\n%q", out, want)
+	}
+}
+
+func TestFuncs_FormatDateRejectsNonDates(t *testing.T) {
+	tpl, err := NewTemplate("t").Parse(`{{ formatDate "short" 42 }}`)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	if _, err := RenderEditor(tpl, nil); err == nil {
+		t.Fatalf("expected an error for a non-date argument")
+	}
+}
+
+func TestFuncs_Library(t *testing.T) {
+	data := map[string]any{
+		"Skills":  []string{"Go", "Rust", "SQL"},
+		"One":     []string{"Go"},
+		"Empty":   "",
+		"Manager": "Sam",
+		"Experience": []config.Experience{
+			{Company: "initech", StartDate: config.NewDate("2019"), Tags: []string{"Backend"}},
+			{Company: "ACME", StartDate: config.NewDate("2022-06"), Tags: []string{"frontend"}},
+			{Company: "Globex", StartDate: config.NewDate("2020-01")},
+		},
+		"Custom": []any{
+			map[string]any{"name": "b", "tags": "go, cli"},
+			map[string]any{"name": "a", "tags": []any{"web"}},
+		},
+	}
+	tests := []struct{ src, want string }{
+		{`{{ .Skills | join ", " }}`, "Go, Rust, SQL"},
+		{`{{ oxford .Skills }}|{{ .Skills | limit 2 | oxford }}|{{ oxford .One }}`, "Go, Rust, and SQL|Go and Rust|Go"},
+		{`{{ upper "go" }} {{ lower "GO" }} {{ title "senior go-developer" }}`, "GO go Senior Go-Developer"},
+		{`{{ .Empty | default "Hiring Manager" }}, {{ .Manager | default "x" }}, {{ .Missing | default "y" }}`, "Hiring Manager, Sam, y"},
+		{`{{ pluralize .One "skill" }} {{ pluralize .Skills "skill" }} {{ pluralize 2 "company" }} {{ pluralize 0 "box" }} {{ pluralize 3 "person" "people" }}`, "skill skills companies boxes people"},
+		{`{{ truncateWords 3 "one two three four" }}|{{ truncateWords 5 "one two" }}`, "one two three…|one two"},
+		{`{{ indent 2 "a\n\nb" }}`, "  a\n\n  b"},
+		{`{{ wrap 10 "the quick brown fox jumps\nover" }}`, "the quick\nbrown fox\njumps\nover"},
+		{`{{ first .Skills }} {{ last .Skills }} {{ first .Nothing }}`, "Go SQL <no value>"},
+		{`{{ range sortBy "StartDate" .Experience }}{{ .Company }} {{ end }}`, "initech Globex ACME "},
+		{`{{ range sortBy "-company" .Experience }}{{ .Company }} {{ end }}`, "initech Globex ACME "},
+		{`{{ range sortBy "name" .Custom }}{{ .name }}{{ end }}`, "ab"},
+		{`{{ range filterByTag "backend" .Experience }}{{ .Company }}{{ end }}`, "initech"},
+		{`{{ range filterByTag "go" .Custom }}{{ .name }}{{ end }}{{ range filterByTag "web" .Custom }}{{ .name }}{{ end }}`, "ba"},
+		{`{{ date "2006-01-02" "2022-06-15" }} {{ date "short" .Manager }}`, "2022-06-15 Sam"},
+	}
+	for _, tt := range tests {
+		tpl, err := NewTemplate("t").Parse(tt.src)
+		if err != nil {
+			t.Fatalf("parse %s: %v", tt.src, err)
+		}
+		out, err := RenderEditor(tpl, data)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("%s = %q, want %q", tt.src, out, tt.want)
+		}
+	}
+}
+
+func TestFuncs_Now(t *testing.T) {
+	tpl, err := NewTemplate("t").Parse(`{{ now | date "year" }}`)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	out, err := RenderEditor(tpl, nil)
+	if err != nil || string(out) != time.Now().Format("2006") {
+		t.Fatalf("got %q, %v", out, err)
+	}
+}
+
+func TestFuncs_ListErrors(t *testing.T) {
+	for _, src := range []string{`{{ join ", " 42 }}`, `{{ sortBy "Nope" .Experience }}`, `{{ pluralize true "skill" }}`} {
+		tpl, err := NewTemplate("t").Parse(src)
+		if err != nil {
+			t.Fatalf("parse %s: %v", src, err)
+		}
+		data := map[string]any{"Experience": []config.Experience{{Company: "ACME"}}, "Skills": 1.5}
+		if _, err := RenderEditor(tpl, data); err == nil {
+			t.Errorf("%s: expected an error", src)
+		}
+	}
+}
+
+func TestFuncDocs_CoverFuncs(t *testing.T) {
+	documented := map[string]bool{}
+	for _, d := range FuncDocs {
+		if _, ok := Funcs()[d.Name]; !ok {
+			t.Errorf("FuncDocs documents unknown helper %q", d.Name)
+		}
+		if !strings.HasPrefix(d.Usage, d.Name) || d.Summary == "" {
+			t.Errorf("incomplete doc for %q", d.Name)
+		}
+		if _, err := NewTemplate("t").Parse(d.Example); err != nil {
+			t.Errorf("example for %q does not parse: %v", d.Name, err)
+		}
+		documented[d.Name] = true
+	}
+	for name := range Funcs() {
+		if !documented[name] {
+			t.Errorf("helper %q is missing from FuncDocs", name)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// ErrLayoutCycle is returned when layouts extend each other in a loop.
+var ErrLayoutCycle = errors.New("layout cycle")
+
+// extendsRe matches the {{/* extends "layouts/formal.tpl" */}} directive that
+// must open a template extending a layout.
+var extendsRe = regexp.MustCompile(`^\s*\{\{-?\s*/\*\s*extends\s+"([^"]+)"\s*\*/\s*-?\}\}`)
+
+// extendsOf returns the layout named by text's extends directive, if any.
+func extendsOf(text string) string {
+	if m := extendsRe.FindStringSubmatch(text); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+// Letter is a template parsed with its partials and layouts.
+type Letter struct {
+	// Template executes the letter: the outermost layout when it extends
+	// one, otherwise the letter itself. All templates share one set.
+	Template *template.Template
+	// Layouts is the extends chain, innermost first, named relative to the
+	// templates directory.
+	Layouts []string
+	// Blocks maps the named templates in the set, other than the letter and
+	// its layouts, to the file whose definition is used; a later definition
+	// replaces an earlier one.
+	Blocks map[string]string
+	// Meta is the letter's front matter, with the metadata its layouts
+	// declare filled in; see FrontMatter.inherit.
+	Meta FrontMatter
+	// files maps the parse names of the set's trees to their files, named
+	// like Blocks, for locating template errors.
+	files map[string]string
+}
+
+// BlockNames returns the names in Blocks, sorted.
+func (l *Letter) BlockNames() []string {
+	names := make([]string, 0, len(l.Blocks))
+	for name := range l.Blocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load parses text, the contents of the template file at path, with the
+// partials (and siblings, if enabled) and the chain of layouts it extends.
+// path may be empty for an unsaved buffer; it names the template and locates
+// its siblings.
+//
+// A template extends a layout by opening with {{/* extends "NAME" */}}, or
+// with front matter naming the layout, where NAME is relative to the
+// templates directory and may leave off the extension. Front matter of .tpl
+// and .tmpl files is stripped before parsing. It then only overrides the layout's {{ block }}s with
+// {{ define }}; text outside them is an error. Layouts may extend layouts,
+// the outermost one is executed, and definitions in the letter win over
+// those of its layouts, which win over partials.
+func (r *Renderer) Load(path, text string) (*Letter, error) {
+	name := filepath.Base(path)
+	if path == "" {
+		name = "letter"
+	}
+	l := &loader{r: r, t: NewTemplate(name), path: path, blocks: map[string]string{}, files: map[string]string{}}
+	if r.TemplatesDir != "" {
+		if err := l.addPartials(filepath.Join(r.TemplatesDir, PartialsDir)); err != nil {
+			return nil, err
+		}
+	}
+	if r.Siblings && path != "" {
+		if err := l.addSiblings(); err != nil {
+			return nil, err
+		}
+	}
+
+	meta, body, layout, err := splitTemplate(path, text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	chain, err := r.layoutChain(path, layout)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		lf := chain[i]
+		t := l.t.New(lf.name)
+		if err := l.add(t, lf.path, lf.text); err != nil {
+			return nil, fmt.Errorf("layout %s: %w", lf.path, err)
+		}
+		if i < len(chain)-1 && !onlyDefines(t) {
+			return nil, fmt.Errorf("layout %s extends %s: text outside {{ define }} is not allowed", lf.name, chain[i+1].name)
+		}
+	}
+	if err := l.add(l.t, path, body); err != nil {
+		return nil, err
+	}
+
+	letter := &Letter{Template: l.t, Blocks: l.blocks, Meta: meta, files: l.files}
+	delete(l.blocks, name)
+	if len(chain) > 0 {
+		if !onlyDefines(l.t) {
+			return nil, fmt.Errorf("%s extends %s: text outside {{ define }} is not allowed", name, chain[0].name)
+		}
+		for _, lf := range chain {
+			letter.Layouts = append(letter.Layouts, lf.name)
+			letter.Meta.inherit(lf.meta)
+			delete(l.blocks, lf.name)
+		}
+		letter.Template = l.t.Lookup(chain[len(chain)-1].name)
+	}
+	return letter, nil
+}
+
+// loader builds the template set for Load.
+type loader struct {
+	r *Renderer
+	// t is the set's root, the letter itself
+	t *template.Template
+	// path is the letter's file, or empty
+	path   string
+	blocks map[string]string
+	files  map[string]string
+}
+
+// add parses text from file into t and records file as the source of every
+// template the parse defined or replaced.
+func (l *loader) add(t *template.Template, file, text string) error {
+	before := map[string]*parse.Tree{}
+	for _, x := range l.t.Templates() {
+		before[x.Name()] = x.Tree
+	}
+	if _, err := t.Parse(text); err != nil {
+		return err
+	}
+	source := l.t.Name()
+	if file != "" {
+		source = l.r.relName(file)
+	}
+	l.files[t.Name()] = source
+	for _, x := range l.t.Templates() {
+		if x.Tree != nil && x.Tree != before[x.Name()] {
+			l.blocks[x.Name()] = source
+		}
+	}
+	return nil
+}
+
+// onlyDefines reports whether t's top-level text is empty, so that the file
+// it was parsed from holds nothing but {{ define }}s and comments.
+func onlyDefines(t *template.Template) bool {
+	return t == nil || t.Tree == nil || parse.IsEmptyTree(t.Tree.Root)
+}
+
+// splitTemplate strips the front matter of a template file that may have
+// one and returns it, the text to parse and the layout the file extends.
+func splitTemplate(path, text string) (fm FrontMatter, body, layout string, err error) {
+	body, rest := text, text
+	if hasFrontMatter(path) {
+		if fm, body, rest, err = splitFrontMatter(text); err != nil {
+			return fm, "", "", err
+		}
+	}
+	layout = fm.Layout
+	if layout == "" {
+		layout = extendsOf(rest)
+	}
+	return fm, body, layout, nil
+}
+
+type layoutFile struct {
+	name, path, text string
+	meta             FrontMatter
+}
+
+// layoutChain follows the layouts from ref, the one the letter at path
+// extends, innermost first.
+func (r *Renderer) layoutChain(path, ref string) ([]layoutFile, error) {
+	var chain []layoutFile
+	seen := map[string]bool{}
+	trail := []string{"letter"}
+	if path != "" {
+		seen[absPath(path)] = true
+		trail[0] = r.relName(path)
+	}
+	from := path
+	for ref != "" {
+		lp, err := r.resolveLayout(ref, from)
+		if err != nil {
+			return nil, err
+		}
+		name := r.relName(lp)
+		trail = append(trail, name)
+		if seen[absPath(lp)] {
+			return nil, fmt.Errorf("%w: %s", ErrLayoutCycle, strings.Join(trail, " -> "))
+		}
+		seen[absPath(lp)] = true
+		b, err := os.ReadFile(lp)
+		if err != nil {
+			return nil, err
+		}
+		lf := layoutFile{name: name, path: lp}
+		if lf.meta, lf.text, ref, err = splitTemplate(lp, string(b)); err != nil {
+			return nil, fmt.Errorf("layout %s: %w", name, err)
+		}
+		chain = append(chain, lf)
+		from = lp
+	}
+	return chain, nil
+}
+
+// resolveLayout finds the layout ref named in the file from: a path relative
+// to the templates directory (or, without one, to from's directory), with or
+// without its extension.
+func (r *Renderer) resolveLayout(ref, from string) (string, error) {
+	p := filepath.FromSlash(ref)
+	if !filepath.IsAbs(p) {
+		base := r.TemplatesDir
+		if base == "" {
+			base = filepath.Dir(from)
+		}
+		p = filepath.Join(base, p)
+	}
+	candidates := []string{p}
+	if filepath.Ext(p) == "" {
+		for _, ext := range TemplateExts {
+			candidates = append(candidates, p+ext)
+		}
+	}
+	for _, c := range candidates {
+		if fi, err := os.Stat(c); err == nil && !fi.IsDir() {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("layout %q not found", ref)
+}
+
+// relName names a file relative to the templates directory when it is inside it.
+func (r *Renderer) relName(path string) string {
+	if r.TemplatesDir != "" {
+		if rel, err := filepath.Rel(r.TemplatesDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return path
+}
+
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return filepath.Clean(p)
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Layouts(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"partials/signature.tpl": "-- {{ .Name }}",
+		"layouts/base.tpl":       `Dear {{ block "greeting" . }}Hiring Manager{{ end }},{{ "\n" }}{{ block "body" . }}{{ end }}{{ "\n" }}{{ block "closing" . }}{{ template "signature" . }}{{ end }}`,
+		"layouts/formal.tpl": `{{/* extends "layouts/base" */}}
+{{ define "body" }}I am applying for {{ .RoleToApplyTo }}. {{ block "why" . }}I like it.{{ end }}{{ end }}`,
+	})
+	letter := `{{/* extends "layouts/formal.tpl" */}}
+{{ define "why" }}Go is great.{{ end }}
+{{ define "greeting" }}{{ .HiringManager }}{{ end }}`
+	path := filepath.Join(dir, "letters", "acme.tpl")
+	l, err := (&Renderer{TemplatesDir: dir}).Load(path, letter)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	out, err := RenderEditor(l.Template, map[string]any{"Name": "Jane", "RoleToApplyTo": "Engineer", "HiringManager": "Sam"})
+	if err != nil {
+		t.Fatalf("RenderEditor: %v", err)
+	}
+	if want := "Dear Sam,\nI am applying for Engineer. Go is great.\n-- Jane"; string(out) != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+	if want := []string{"layouts/formal.tpl", "layouts/base.tpl"}; !reflect.DeepEqual(l.Layouts, want) {
+		t.Fatalf("Layouts = %v, want %v", l.Layouts, want)
+	}
+	for block, file := range map[string]string{
+		"greeting":  "letters/acme.tpl",
+		"why":       "letters/acme.tpl",
+		"body":      "layouts/formal.tpl",
+		"closing":   "layouts/base.tpl",
+		"signature": "partials/signature.tpl",
+	} {
+		if l.Blocks[block] != file {
+			t.Errorf("block %q came from %q, want %q", block, l.Blocks[block], file)
+		}
+	}
+}
+
+func TestRenderer_LayoutCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"layouts/a.tpl": `{{/* extends "layouts/b" */}}`,
+		"layouts/b.tpl": `{{/* extends "layouts/a" */}}`,
+	})
+	_, err := (&Renderer{TemplatesDir: dir}).Load(filepath.Join(dir, "letter.tpl"), `{{/* extends "layouts/a" */}}`)
+	if !errors.Is(err, ErrLayoutCycle) || !strings.Contains(err.Error(), "letter.tpl -> layouts/a.tpl -> layouts/b.tpl -> layouts/a.tpl") {
+		t.Fatalf("expected a cycle error naming the chain, got %v", err)
+	}
+	_, err = (&Renderer{TemplatesDir: dir}).Load(filepath.Join(dir, "layouts", "a.tpl"), `{{/* extends "layouts/a" */}}`)
+	if !errors.Is(err, ErrLayoutCycle) {
+		t.Fatalf("expected a template extending itself to be a cycle, got %v", err)
+	}
+}
+
+func TestRenderer_LayoutErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"layouts/base.tpl": `{{ block "body" . }}{{ end }}`})
+	r := &Renderer{TemplatesDir: dir}
+	if _, err := r.Load("", `{{/* extends "layouts/nope" */}}`); err == nil || !strings.Contains(err.Error(), `layout "layouts/nope" not found`) {
+		t.Fatalf("expected a missing layout error, got %v", err)
+	}
+	if _, err := r.Load("", "{{/* extends \"layouts/base\" */}}\nstray text{{ define \"body\" }}x{{ end }}"); err == nil || !strings.Contains(err.Error(), "outside {{ define }}") {
+		t.Fatalf("expected text outside define to be rejected, got %v", err)
+	}
+	l, err := r.Load("", "Hi {{/* extends \"layouts/base\" */}}")
+	if err != nil || len(l.Layouts) != 0 {
+		t.Fatalf("expected a directive after text to be ignored, got %v, %v", l, err)
+	}
+}
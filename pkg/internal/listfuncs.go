@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"covlet/pkg/config"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// toList returns v as a slice value; nil is an empty list.
+func toList(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return rv, nil
+	case reflect.Invalid:
+		return reflect.ValueOf([]any{}), nil
+	}
+	return reflect.Value{}, fmt.Errorf("expected a list, got %T", v)
+}
+
+func strs(v any) ([]string, error) {
+	if s, ok := v.([]string); ok {
+		return s, nil
+	}
+	rv, err := toList(v)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, rv.Len())
+	for i := range out {
+		out[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return out, nil
+}
+
+// join joins the items of a list with sep: {{ .Skills | join ", " }}.
+func join(sep string, list any) (string, error) {
+	items, err := strs(list)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(items, sep), nil
+}
+
+// oxford joins the items of a list as English prose: "Go", "Go and Rust",
+// "Go, Rust, and SQL".
+func oxford(list any) (string, error) {
+	items, err := strs(list)
+	if err != nil {
+		return "", err
+	}
+	switch len(items) {
+	case 0:
+		return "", nil
+	case 1:
+		return items[0], nil
+	case 2:
+		return items[0] + " and " + items[1], nil
+	}
+	return strings.Join(items[:len(items)-1], ", ") + ", and " + items[len(items)-1], nil
+}
+
+// first returns the first item of a list, or nil if it is empty.
+func first(list any) (any, error) {
+	rv, err := toList(list)
+	if err != nil || rv.Len() == 0 {
+		return nil, err
+	}
+	return rv.Index(0).Interface(), nil
+}
+
+// last returns the last item of a list, or nil if it is empty.
+func last(list any) (any, error) {
+	rv, err := toList(list)
+	if err != nil || rv.Len() == 0 {
+		return nil, err
+	}
+	return rv.Index(rv.Len() - 1).Interface(), nil
+}
+
+// limit returns at most the first n items of a list.
+func limit(n int, list any) (any, error) {
+	rv, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		n = 0
+	}
+	if rv.Len() <= n {
+		return rv.Interface(), nil
+	}
+	return rv.Slice(0, n).Interface(), nil
+}
+
+// sortBy returns a copy of a list of entries sorted by field, a field name or
+// yaml key ("Company", "start_date"); a leading "-" sorts in descending order.
+// Dates sort chronologically and text ignores case. The sort is stable.
+func sortBy(field string, list any) (any, error) {
+	rv, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	desc := strings.HasPrefix(field, "-")
+	field = strings.TrimPrefix(field, "-")
+	keys := make([]any, rv.Len())
+	for i := range keys {
+		f, ok := fieldOf(rv.Index(i), field)
+		if !ok {
+			return nil, fmt.Errorf("sortBy: item %d has no field %q", i, field)
+		}
+		keys[i] = f.Interface()
+	}
+	idx := make([]int, len(keys))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		if desc {
+			return less(keys[idx[b]], keys[idx[a]])
+		}
+		return less(keys[idx[a]], keys[idx[b]])
+	})
+	sorted := reflect.MakeSlice(reflect.SliceOf(rv.Type().Elem()), len(idx), len(idx))
+	for i, j := range idx {
+		sorted.Index(i).Set(rv.Index(j))
+	}
+	return sorted.Interface(), nil
+}
+
+// less orders sort keys: dates by time, numbers by value, anything else by
+// its text, ignoring case.
+func less(a, b any) bool {
+	if ta, ok := sortTime(a); ok {
+		if tb, ok := sortTime(b); ok {
+			return ta.Before(tb)
+		}
+	}
+	if fa, ok := sortNumber(a); ok {
+		if fb, ok := sortNumber(b); ok {
+			return fa < fb
+		}
+	}
+	return strings.ToLower(fmt.Sprint(a)) < strings.ToLower(fmt.Sprint(b))
+}
+
+func sortTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case config.Date:
+		return t.Time(), true
+	}
+	return time.Time{}, false
+}
+
+func sortNumber(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// filterByTag returns the entries of a list whose tags include tag, ignoring
+// case: {{ range filterByTag "backend" .Experience }}. Tags are read from a
+// Tags field or a tags key, as a list or comma separated text.
+func filterByTag(tag string, list any) (any, error) {
+	rv, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	out := reflect.MakeSlice(reflect.SliceOf(rv.Type().Elem()), 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		f, ok := fieldOf(rv.Index(i), "Tags")
+		if !ok {
+			continue
+		}
+		var tags []string
+		if s, isString := f.Interface().(string); isString {
+			tags = config.SplitList(s)
+		} else if tags, err = strs(f.Interface()); err != nil {
+			return nil, fmt.Errorf("filterByTag: item %d: tags: %w", i, err)
+		}
+		for _, t := range tags {
+			if strings.EqualFold(strings.TrimSpace(t), tag) {
+				out = reflect.Append(out, rv.Index(i))
+				break
+			}
+		}
+	}
+	return out.Interface(), nil
+}
+
+// fieldOf looks up name in a struct (by field name or yaml key) or a map
+// with string keys, ignoring case.
+func fieldOf(v reflect.Value, name string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tag, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+			if sf.IsExported() && (strings.EqualFold(sf.Name, name) || (tag != "" && strings.EqualFold(tag, name))) {
+				return v.Field(i), true
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if strings.EqualFold(iter.Key().String(), name) {
+				f := iter.Value()
+				for f.Kind() == reflect.Interface && !f.IsNil() {
+					f = f.Elem()
+				}
+				return f, f.IsValid()
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
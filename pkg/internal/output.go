@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format is an output format for a rendered letter.
+type Format string
+
+const (
+	FormatText     Format = "txt"
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+	FormatPDF      Format = "pdf"
+)
+
+// Formats lists the supported output formats.
+var Formats = []Format{FormatText, FormatMarkdown, FormatHTML, FormatPDF}
+
+// ParseFormat returns the Format named s ("txt", "md", "html" or "pdf").
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q (use txt, md, html or pdf)", s)
+}
+
+// FormatOf picks the output format from a file extension; anything unknown is text.
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".html", ".htm":
+		return FormatHTML
+	case ".pdf":
+		return FormatPDF
+	}
+	return FormatText
+}
+
+// WriteOutput writes rendered text to w in format f. Text and Markdown are
+// written as rendered; HTML output of a plain text template is wrapped in a
+// page with one paragraph per blank-line separated block; PDF gets title
+// (if any) as its heading, as in SaveTextAsPDF.
+func WriteOutput(w io.Writer, text []byte, f Format, title string) error {
+	switch f {
+	case FormatHTML:
+		if looksLikeHTML(text) {
+			_, err := w.Write(text)
+			return err
+		}
+		_, err := io.WriteString(w, textToHTML(string(text), title))
+		return err
+	case FormatPDF:
+		return newTextPDF(title, string(text)).Output(w)
+	}
+	_, err := w.Write(text)
+	return err
+}
+
+func looksLikeHTML(text []byte) bool {
+	t := bytes.ToLower(bytes.TrimSpace(text))
+	return bytes.HasPrefix(t, []byte("<!doctype")) || bytes.HasPrefix(t, []byte("<html"))
+}
+
+func textToHTML(text, title string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	if title != "" {
+		fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(title))
+	}
+	b.WriteString("</head>\n<body>\n")
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if para = strings.Trim(para, "\n"); strings.TrimSpace(para) == "" {
+			continue
+		}
+		lines := strings.Split(para, "\n")
+		for i, l := range lines {
+			lines[i] = html.EscapeString(l)
+		}
+		fmt.Fprintf(&b, "<p>%s</p>\n", strings.Join(lines, "<br>\n"))
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]Format{
+		"letter.pdf":  FormatPDF,
+		"letter.HTML": FormatHTML,
+		"letter.md":   FormatMarkdown,
+		"letter.txt":  FormatText,
+		"letter":      FormatText,
+		"":            FormatText,
+	}
+	for path, want := range tests {
+		if got := FormatOf(path); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if _, err := ParseFormat("docx"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestWriteOutput_HTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteOutput(&buf, []byte("Dear Sam & team,\n\nI <3 Go.\nThanks\n"), FormatHTML, "Letter"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"<title>Letter</title>", "<p>Dear Sam &amp; team,</p>", "<p>I &lt;3 Go.<br>\nThanks</p>"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	// html templates are written as rendered
+	buf.Reset()
+	page := "<!DOCTYPE html><p>hi</p>"
+	if err := WriteOutput(&buf, []byte(page), FormatHTML, ""); err != nil || buf.String() != page {
+		t.Fatalf("got %q, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// PartialsDir is the directory under the templates root whose files are
+// parsed into every template.
+const PartialsDir = "partials"
+
+// TemplateExts are the extensions of template files, in the order they are
+// tried when a template is named without one.
+var TemplateExts = []string{".tpl", ".tmpl", ".gohtml", ".html", ".md", ".txt"}
+
+// IsTemplateFile reports whether path has a template extension.
+func IsTemplateFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range TemplateExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Renderer parses letters together with the shared partials, so a letter can
+// use {{ template "signature" . }}, and the layouts they extend.
+type Renderer struct {
+	// TemplatesDir is the templates root; every file under its partials
+	// directory is added to each template, named by its path relative to
+	// that directory without extension ("signature", "closings/formal").
+	// Templates defined inside a partial with {{ define }} are added too.
+	// Layouts are named relative to it.
+	TemplatesDir string
+	// Siblings also adds the other template files in the directory of the
+	// parsed template, named by their base name without extension.
+	Siblings bool
+}
+
+// Parse parses text, the contents of the template file at path, into a set
+// with the partials and the layouts it extends; see Load.
+func (r *Renderer) Parse(path, text string) (*template.Template, error) {
+	l, err := r.Load(path, text)
+	if err != nil {
+		return nil, err
+	}
+	return l.Template, nil
+}
+
+// ParseFile reads and parses the template file at path; see Parse.
+func (r *Renderer) ParseFile(path string) (*template.Template, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return r.Parse(path, string(b))
+}
+
+// addPartials parses every template file under dir into the set, skipping
+// the letter itself.
+func (l *loader) addPartials(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == dir {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && p != dir {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !IsTemplateFile(p) || sameFile(p, l.path) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		return l.addFile(strings.TrimSuffix(filepath.ToSlash(rel), filepath.Ext(rel)), p)
+	})
+}
+
+// addSiblings parses the other template files next to the letter into the set.
+func (l *loader) addSiblings() error {
+	dir := filepath.Dir(l.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !IsTemplateFile(p) || sameFile(p, l.path) {
+			continue
+		}
+		if err := l.addFile(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())), p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *loader) addFile(name, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	text := string(b)
+	if hasFrontMatter(path) {
+		if _, text, err = ParseFrontMatter(text); err != nil {
+			return fmt.Errorf("partial %s: %w", path, err)
+		}
+	}
+	if err := l.add(l.t.New(name), path, text); err != nil {
+		return fmt.Errorf("partial %s: %w", path, err)
+	}
+	return nil
+}
+
+func sameFile(a, b string) bool {
+	if b == "" {
+		return false
+	}
+	ai, err1 := os.Stat(a)
+	bi, err2 := os.Stat(b)
+	return err1 == nil && err2 == nil && os.SameFile(ai, bi)
+}
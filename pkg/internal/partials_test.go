@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRenderer_Partials(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"partials/signature.tpl":        "-- {{ .Name }}",
+		"partials/closings/formal.tmpl": `{{ define "regards" }}Kind regards{{ end }}Yours faithfully`,
+		"partials/notes.yml":            "not a template",
+		"letters/intro.tpl":             "Hi {{ .Name }}",
+	})
+	r := &Renderer{TemplatesDir: dir}
+	tpl, err := r.Parse(filepath.Join(dir, "letters", "acme.tpl"),
+		`{{ template "closings/formal" . }}. {{ template "regards" . }}, {{ template "signature" . }}`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	out, err := RenderEditor(tpl, map[string]any{"Name": "Jane"})
+	if err != nil {
+		t.Fatalf("RenderEditor: %v", err)
+	}
+	if want := "Yours faithfully. Kind regards, -- Jane"; string(out) != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+	if tpl.Lookup("intro") != nil || tpl.Lookup("notes") != nil {
+		t.Fatalf("expected only partials to be loaded, got %s", tpl.DefinedTemplates())
+	}
+}
+
+func TestRenderer_LocalDefineWins(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"partials/signature.tpl": "-- shared"})
+	tpl, err := (&Renderer{TemplatesDir: dir}).Parse("", `{{ define "signature" }}-- local{{ end }}{{ template "signature" }}`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if out, _ := RenderEditor(tpl, nil); string(out) != "-- local" {
+		t.Fatalf("got %q, want the local definition", out)
+	}
+}
+
+func TestRenderer_Siblings(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"letters/intro.tpl": "Hi {{ .Name }}",
+		"letters/acme.tpl":  `{{ template "intro" . }}!`,
+	})
+	path := filepath.Join(dir, "letters", "acme.tpl")
+	if _, err := (&Renderer{TemplatesDir: dir}).ParseFile(path); err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	tpl, err := (&Renderer{TemplatesDir: dir, Siblings: true}).ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if out, err := RenderEditor(tpl, map[string]any{"Name": "Jane"}); err != nil || string(out) != "Hi Jane!" {
+		t.Fatalf("got %q, %v", out, err)
+	}
+	if tpl.Lookup("acme") != nil {
+		t.Fatalf("the template itself should not be loaded as a sibling")
+	}
+}
+
+func TestRenderer_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := (&Renderer{TemplatesDir: dir}).Parse("", "no partials dir is fine"); err != nil {
+		t.Fatalf("expected a missing partials dir to be ignored, got %v", err)
+	}
+	writeFiles(t, dir, map[string]string{"partials/broken.tpl": "{{ .Name "})
+	_, err := (&Renderer{TemplatesDir: dir}).Parse("", "text")
+	if err == nil || !strings.Contains(err.Error(), "broken.tpl") {
+		t.Fatalf("expected the broken partial to be named, got %v", err)
+	}
+	if _, err := (&Renderer{}).Parse("", "{{ .Name "); err == nil {
+		t.Fatalf("expected a parse error")
+	}
+}
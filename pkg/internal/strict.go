@@ -0,0 +1,301 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+)
+
+// Unresolved is a template reference strict rendering could not resolve.
+type Unresolved struct {
+	// File is the template file, relative to the templates directory when
+	// inside it; Line and Col locate the reference in it.
+	File      string
+	Line, Col int
+	// Ref is the reference as written, e.g. ".HiringManager".
+	Ref string
+	// Reason is "is not set", "does not exist" or "is empty".
+	Reason string
+}
+
+func (u Unresolved) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s %s", u.File, u.Line, u.Col, u.Ref, u.Reason)
+}
+
+// StrictError lists every reference a strict render could not resolve, in
+// the order they were met. Err is set when execution stopped early.
+type StrictError struct {
+	Unresolved []Unresolved
+	Err        error
+}
+
+func (e *StrictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d unresolved %s", len(e.Unresolved), mustPluralize(len(e.Unresolved), "reference"))
+	for _, u := range e.Unresolved {
+		b.WriteString("\n  " + u.String())
+	}
+	if e.Err != nil {
+		b.WriteString("\n  " + e.Err.Error())
+	}
+	return b.String()
+}
+
+func (e *StrictError) Unwrap() error { return e.Err }
+
+func mustPluralize(n int, word string) string {
+	s, _ := pluralize(n, word)
+	return s
+}
+
+// strictFunc is the helper the rewritten templates call for every reference.
+const strictFunc = "strictRef"
+
+// RenderStrict executes the letter like RenderEditor but fails unless every
+// reference resolves: a missing map key or field ("is not set", "does not
+// exist") anywhere, or an empty value where one is printed ("is empty").
+// References handed to default are exempt. Instead of stopping at the first
+// problem it keeps going and returns a *StrictError listing them all.
+func (l *Letter) RenderStrict(data any) ([]byte, error) {
+	run := &strictRun{files: l.files}
+	set := NewTemplate(l.Template.Name()).Option("missingkey=error")
+	for _, t := range l.Template.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		tree := t.Tree.Copy()
+		run.rewrite(tree, tree.Root)
+		if _, err := set.AddParseTree(t.Name(), tree); err != nil {
+			return nil, err
+		}
+	}
+	set.Funcs(template.FuncMap{strictFunc: run.ref})
+	out, err := RenderEditor(set.Lookup(l.Template.Name()), data)
+	if err != nil || len(run.found) > 0 {
+		return nil, &StrictError{Unresolved: run.found, Err: err}
+	}
+	return out, nil
+}
+
+// strictRun rewrites template trees so each reference goes through ref, and
+// collects what ref finds.
+type strictRun struct {
+	// files maps template parse names to their files, as in Letter.Blocks
+	files map[string]string
+	sites []strictSite
+
+	mu    sync.Mutex
+	seen  map[Unresolved]bool
+	found []Unresolved
+}
+
+// strictSite is a rewritten reference.
+type strictSite struct {
+	file      string
+	line, col int
+	ref       string
+	// printed references must not be empty
+	printed bool
+}
+
+func (s *strictRun) rewrite(tree *parse.Tree, n parse.Node) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			s.rewrite(tree, c)
+		}
+	case *parse.ActionNode:
+		s.rewritePipe(tree, n.Pipe, len(n.Pipe.Decl) == 0 && !usesDefault(n.Pipe))
+	case *parse.IfNode:
+		s.rewriteBranch(tree, &n.BranchNode)
+	case *parse.RangeNode:
+		s.rewriteBranch(tree, &n.BranchNode)
+	case *parse.WithNode:
+		s.rewriteBranch(tree, &n.BranchNode)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			s.rewritePipe(tree, n.Pipe, false)
+		}
+	}
+}
+
+func (s *strictRun) rewriteBranch(tree *parse.Tree, b *parse.BranchNode) {
+	s.rewritePipe(tree, b.Pipe, false)
+	s.rewrite(tree, b.List)
+	s.rewrite(tree, b.ElseList)
+}
+
+// rewritePipe replaces field references in p with calls to the strict
+// helper. A field that takes arguments, or the pipeline's final value, is a
+// method call and is left alone.
+func (s *strictRun) rewritePipe(tree *parse.Tree, p *parse.PipeNode, printed bool) {
+	for ci, cmd := range p.Cmds {
+		for i, arg := range cmd.Args {
+			if i == 0 && (len(cmd.Args) > 1 || ci > 0) {
+				if sub, ok := arg.(*parse.PipeNode); ok {
+					s.rewritePipe(tree, sub, printed)
+				}
+				continue
+			}
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				cmd.Args[i] = s.call(tree, a, 0, &parse.DotNode{Pos: a.Pos}, a.Ident, printed)
+			case *parse.VariableNode:
+				if len(a.Ident) > 1 {
+					v := &parse.VariableNode{NodeType: parse.NodeVariable, Pos: a.Pos, Ident: a.Ident[:1]}
+					// a chained variable is positioned at its first field
+					cmd.Args[i] = s.call(tree, a, len(a.Ident[0]), v, a.Ident[1:], printed)
+				}
+			case *parse.PipeNode:
+				s.rewritePipe(tree, a, printed)
+			}
+		}
+	}
+}
+
+// call builds (strictRef "SITE" DOT "NAME"...) for the reference orig,
+// which starts lead bytes before its position.
+func (s *strictRun) call(tree *parse.Tree, orig parse.Node, lead int, dot parse.Node, names []string, printed bool) parse.Node {
+	loc, _ := tree.ErrorContext(orig)
+	site := strictSite{ref: orig.String(), printed: printed}
+	site.file, site.line, site.col = s.location(loc)
+	site.col -= lead
+	s.sites = append(s.sites, site)
+
+	pos := orig.Position()
+	str := func(text string) parse.Node {
+		return &parse.StringNode{NodeType: parse.NodeString, Pos: pos, Quoted: strconv.Quote(text), Text: text}
+	}
+	args := []parse.Node{parse.NewIdentifier(strictFunc).SetTree(tree).SetPos(pos), str(strconv.Itoa(len(s.sites) - 1)), dot}
+	for _, name := range names {
+		args = append(args, str(name))
+	}
+	return &parse.PipeNode{NodeType: parse.NodePipe, Pos: pos, Cmds: []*parse.CommandNode{
+		{NodeType: parse.NodeCommand, Pos: pos, Args: args},
+	}}
+}
+
+// location splits an ErrorContext location, "name:line:col", maps the
+// template name to its file and makes the column count from one.
+func (s *strictRun) location(loc string) (file string, line, col int) {
+	rest, c, _ := cutLast(loc, ":")
+	name, l, _ := cutLast(rest, ":")
+	line, _ = strconv.Atoi(l)
+	col, _ = strconv.Atoi(c)
+	col++
+	if f, ok := s.files[name]; ok {
+		name = f
+	}
+	return name, line, col
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// ref resolves names from dot like the template would, recording the site
+// when a name is missing or a printed value is empty. Unresolved references
+// evaluate to "" so execution can go on.
+func (s *strictRun) ref(site string, dot any, names ...string) any {
+	i, _ := strconv.Atoi(site)
+	st := s.sites[i]
+	v := reflect.ValueOf(dot)
+	for _, name := range names {
+		next, reason := strictField(v, name)
+		if reason != "" {
+			s.record(st, reason)
+			return ""
+		}
+		v = next
+	}
+	if !v.IsValid() {
+		s.record(st, "is not set")
+		return ""
+	}
+	val := v.Interface()
+	if st.printed && isEmpty(val) {
+		s.record(st, "is empty")
+	}
+	return val
+}
+
+func (s *strictRun) record(st strictSite, reason string) {
+	u := Unresolved{File: st.file, Line: st.line, Col: st.col, Ref: st.ref, Reason: reason}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen == nil {
+		s.seen = map[Unresolved]bool{}
+	}
+	if !s.seen[u] {
+		s.seen[u] = true
+		s.found = append(s.found, u)
+	}
+}
+
+// strictField looks up name in v as text/template does: a method without
+// arguments, a struct field or a map key. The reason is set when it cannot.
+func strictField(v reflect.Value, name string) (reflect.Value, string) {
+	for v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, "is not set"
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return reflect.Value{}, "is not set"
+	}
+	ptr := v
+	if v.Kind() != reflect.Pointer && v.CanAddr() {
+		ptr = v.Addr()
+	}
+	if m := ptr.MethodByName(name); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() >= 1 {
+		out := m.Call(nil)
+		if len(out) == 2 && !out[1].IsNil() {
+			return reflect.Value{}, "is not set"
+		}
+		return out[0], ""
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, "is not set"
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if sf, ok := v.Type().FieldByName(name); ok && sf.IsExported() {
+			return v.FieldByIndex(sf.Index), ""
+		}
+		return reflect.Value{}, "does not exist"
+	case reflect.Map:
+		key := reflect.ValueOf(name)
+		if !key.Type().ConvertibleTo(v.Type().Key()) {
+			return reflect.Value{}, "does not exist"
+		}
+		if mv := v.MapIndex(key.Convert(v.Type().Key())); mv.IsValid() {
+			return mv, ""
+		}
+		return reflect.Value{}, "is not set"
+	}
+	return reflect.Value{}, "does not exist"
+}
+
+// usesDefault reports whether a pipeline passes its value through default.
+func usesDefault(p *parse.PipeNode) bool {
+	for _, cmd := range p.Cmds {
+		if id, ok := cmd.Args[0].(*parse.IdentifierNode); ok && id.Ident == "default" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLetter_RenderStrict(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"partials/signature.tpl": "-- {{ .Name }}",
+	})
+	letter := `---
+title: Strict
+---
+Dear {{ .HiringManager }},
+{{ .HiringManager | default "Hiring Manager" }} at {{ .CompanyToApplyTo }}
+{{ with .Role }}{{ .Title }}{{ end }}{{ range .Skills }}{{ .Name }}{{ end }}
+{{ $r := .Role }}{{ $r.Level }}
+{{ template "signature" . }}`
+	path := filepath.Join(dir, "letter.tpl")
+	l, err := (&Renderer{TemplatesDir: dir}).Load(path, letter)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	data := map[string]any{
+		"HiringManager":    "",
+		"CompanyToApplyTo": "Acme",
+		"Role":             map[string]any{"Title": "Engineer"},
+		"Skills":           []struct{ Name string }{{"Go"}},
+	}
+	_, err = l.RenderStrict(data)
+	var serr *StrictError
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected a *StrictError, got %v", err)
+	}
+	want := []Unresolved{
+		{File: "letter.tpl", Line: 4, Col: 9, Ref: ".HiringManager", Reason: "is empty"},
+		{File: "letter.tpl", Line: 7, Col: 21, Ref: "$r.Level", Reason: "is not set"},
+		{File: "partials/signature.tpl", Line: 1, Col: 7, Ref: ".Name", Reason: "is not set"},
+	}
+	if !reflect.DeepEqual(serr.Unresolved, want) {
+		t.Fatalf("Unresolved =\n%v\nwant\n%v", serr.Unresolved, want)
+	}
+	if serr.Err != nil {
+		t.Fatalf("execution should go on, got %v", serr.Err)
+	}
+
+	data["HiringManager"] = "Sam"
+	data["Name"] = "Jane"
+	data["Role"] = map[string]any{"Title": "Engineer", "Level": "Senior"}
+	out, err := l.RenderStrict(data)
+	if err != nil {
+		t.Fatalf("RenderStrict: %v", err)
+	}
+	if want := "Dear Sam,\nSam at Acme\nEngineerGo\nSenior\n-- Jane"; string(out) != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+}
+
+func TestLetter_RenderStrictFields(t *testing.T) {
+	l, err := (&Renderer{}).Load("", `{{ .Name }}{{ .Missing }}{{ if .Nick }}{{ .Nick }}{{ end }}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	_, err = l.RenderStrict(struct{ Name, Nick string }{Name: "Jane"})
+	var serr *StrictError
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected a *StrictError, got %v", err)
+	}
+	want := []Unresolved{{File: "letter", Line: 1, Col: 15, Ref: ".Missing", Reason: "does not exist"}}
+	if !reflect.DeepEqual(serr.Unresolved, want) {
+		t.Fatalf("Unresolved = %v, want %v", serr.Unresolved, want)
+	}
+}
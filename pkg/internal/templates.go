@@ -25,6 +25,11 @@ func RenderEditor(t *template.Template, data interface{}) ([]byte, error) {
 // SaveTextAsPDF renders the provided plain text into a simple PDF file.
 // It performs basic word wrapping and supports multiple pages.
 func SaveTextAsPDF(title, text, outPath string) error {
+	return newTextPDF(title, text).OutputFileAndClose(outPath)
+}
+
+// newTextPDF lays out title and text on A4 pages.
+func newTextPDF(title, text string) *fpdf.Fpdf {
 	pdf := fpdf.New("P", "mm", "A4", "")
 	pdf.SetTitle(title, true)
 	pdf.SetAuthor("Covlet", true)
@@ -48,5 +53,5 @@ func SaveTextAsPDF(title, text, outPath string) error {
 	// Use 0 width to make it wrap to the page width minus margins
 	pdf.MultiCell(0, 6, text, "", "L", false)
 
-	return pdf.OutputFileAndClose(outPath)
+	return pdf
 }
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// title upper-cases the first letter of every word.
+func title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		start := unicode.IsSpace(prev) || prev == '-'
+		prev = r
+		if start {
+			return unicode.ToTitle(r)
+		}
+		return r
+	}, s)
+}
+
+// defaultValue returns v, or def when v is empty: {{ .HiringManager | default "Hiring Manager" }}.
+func defaultValue(def, v any) any {
+	if isEmpty(v) {
+		return def
+	}
+	return v
+}
+
+// isEmpty reports whether v is nil, a zero value, blank text or an empty list.
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	if s, ok := v.(string); ok {
+		return strings.TrimSpace(s) == ""
+	}
+	if z, ok := v.(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	}
+	return rv.IsZero()
+}
+
+// pluralize returns singular when n is one and the plural otherwise: plural
+// if given, else singular with an English plural ending. n may be a number
+// or a list, whose length counts: {{ len .Skills }} {{ pluralize .Skills "skill" }}.
+func pluralize(n any, singular string, plural ...string) (string, error) {
+	count, err := toCount(n)
+	if err != nil {
+		return "", err
+	}
+	if count == 1 {
+		return singular, nil
+	}
+	if len(plural) > 0 {
+		return plural[0], nil
+	}
+	lower := strings.ToLower(singular)
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return singular + "es", nil
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && strings.IndexByte("aeiou", lower[len(lower)-2]) < 0:
+		return singular[:len(singular)-1] + "ies", nil
+	}
+	return singular + "s", nil
+}
+
+func toCount(n any) (int, error) {
+	rv := reflect.ValueOf(n)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return int(rv.Float()), nil
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return rv.Len(), nil
+	case reflect.Invalid:
+		return 0, nil
+	}
+	return 0, fmt.Errorf("expected a number or a list, got %T", n)
+}
+
+// truncateWords keeps the first n words of s, adding "…" when some were cut.
+func truncateWords(n int, s string) string {
+	words := strings.Fields(s)
+	if n < 0 || len(words) <= n {
+		return s
+	}
+	return strings.Join(words[:n], " ") + "…"
+}
+
+// indent prefixes every non-blank line of s with n spaces.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", max(n, 0))
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			lines[i] = pad + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrap reflows each line of s at spaces so it fits in width characters where
+// possible; words longer than width get a line of their own. Existing line
+// breaks are kept.
+func wrap(width int, s string) string {
+	if width <= 0 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		var b strings.Builder
+		n := 0
+		for _, w := range strings.Fields(l) {
+			wl := utf8.RuneCountInString(w)
+			switch {
+			case n == 0:
+			case n+1+wl > width:
+				b.WriteByte('\n')
+				n = 0
+			default:
+				b.WriteByte(' ')
+				n++
+			}
+			b.WriteString(w)
+			n += wl
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
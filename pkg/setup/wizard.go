@@ -0,0 +1,271 @@
+// Package setup holds the first-run wizard model used to build a resume
+// values file. It has no GUI dependencies so every step can be unit tested.
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strings"
+
+	"covlet/pkg/config"
+)
+
+// Step identifies one page of the setup wizard.
+type Step int
+
+const (
+	StepContact Step = iota
+	StepEducation
+	StepExperience
+	StepSkills
+	StepProjects
+)
+
+var steps = []Step{StepContact, StepEducation, StepExperience, StepSkills, StepProjects}
+
+// Title returns the human readable name of the step.
+func (s Step) Title() string {
+	switch s {
+	case StepContact:
+		return "Contact Information"
+	case StepEducation:
+		return "Education"
+	case StepExperience:
+		return "Experience"
+	case StepSkills:
+		return "Skills"
+	case StepProjects:
+		return "Projects"
+	}
+	return fmt.Sprintf("Step %d", int(s))
+}
+
+// Wizard tracks the current step and the resume being assembled.
+type Wizard struct {
+	step   int
+	Resume config.Resume
+
+	// The Pending entries hold what is typed into a list step's form but not
+	// added yet. Next and Finish add them, so leaving a step keeps them.
+	PendingEducation  config.Education
+	PendingExperience config.Experience
+	PendingProject    config.Project
+}
+
+// New returns a wizard positioned at the first step with an empty resume.
+func New() *Wizard {
+	return &Wizard{}
+}
+
+// Steps returns all steps in display order.
+func (w *Wizard) Steps() []Step {
+	out := make([]Step, len(steps))
+	copy(out, steps)
+	return out
+}
+
+// Step returns the current step.
+func (w *Wizard) Step() Step { return steps[w.step] }
+
+// Index returns the zero-based position of the current step.
+func (w *Wizard) Index() int { return w.step }
+
+// IsFirst reports whether the wizard is on its first step.
+func (w *Wizard) IsFirst() bool { return w.step == 0 }
+
+// IsLast reports whether the wizard is on its last step.
+func (w *Wizard) IsLast() bool { return w.step == len(steps)-1 }
+
+// Next adds the step's pending entry, validates the step and advances to the
+// following one. A pending entry that is filled in but incomplete stops it.
+func (w *Wizard) Next() error {
+	if err := w.addPending(w.Step(), false); err != nil {
+		return err
+	}
+	if err := w.Validate(w.Step()); err != nil {
+		return err
+	}
+	if !w.IsLast() {
+		w.step++
+	}
+	return nil
+}
+
+// Back moves to the previous step without validating.
+func (w *Wizard) Back() {
+	if w.step > 0 {
+		w.step--
+	}
+}
+
+// Validate checks the data entered for a single step.
+func (w *Wizard) Validate(s Step) error {
+	r := w.Resume
+	switch s {
+	case StepContact:
+		if strings.TrimSpace(r.Name) == "" {
+			return errors.New("name is required")
+		}
+		if strings.TrimSpace(r.Email) == "" {
+			return errors.New("email is required")
+		}
+		if _, err := mail.ParseAddress(r.Email); err != nil {
+			return fmt.Errorf("invalid email address %q", r.Email)
+		}
+	case StepEducation:
+		for i, ed := range r.Education {
+			if err := validateEducation(ed); err != nil {
+				return fmt.Errorf("education entry %d: %w", i+1, err)
+			}
+		}
+	case StepExperience:
+		for i, ex := range r.Experience {
+			if err := validateExperience(ex); err != nil {
+				return fmt.Errorf("experience entry %d: %w", i+1, err)
+			}
+		}
+	case StepProjects:
+		for i, p := range r.Projects {
+			if err := validateProject(p); err != nil {
+				return fmt.Errorf("project entry %d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateEducation(ed config.Education) error {
+	if strings.TrimSpace(ed.Institution) == "" {
+		return errors.New("institution is required")
+	}
+	return nil
+}
+
+func validateExperience(ex config.Experience) error {
+	if strings.TrimSpace(ex.Company) == "" {
+		return errors.New("company is required")
+	}
+	if strings.TrimSpace(ex.Position) == "" {
+		return errors.New("position is required")
+	}
+	return nil
+}
+
+func validateProject(p config.Project) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// ValidateAll checks every step and returns the first problem found.
+func (w *Wizard) ValidateAll() error {
+	for _, s := range steps {
+		if err := w.Validate(s); err != nil {
+			return fmt.Errorf("%s: %w", s.Title(), err)
+		}
+	}
+	return nil
+}
+
+// AddEducation appends an education entry, unless it is incomplete.
+func (w *Wizard) AddEducation(e config.Education) error {
+	if err := validateEducation(e); err != nil {
+		return err
+	}
+	w.Resume.Education = append(w.Resume.Education, e)
+	return nil
+}
+
+// AddExperience appends an experience entry, unless it is incomplete.
+func (w *Wizard) AddExperience(e config.Experience) error {
+	if err := validateExperience(e); err != nil {
+		return err
+	}
+	w.Resume.Experience = append(w.Resume.Experience, e)
+	return nil
+}
+
+// AddProject appends a project entry, unless it is incomplete.
+func (w *Wizard) AddProject(p config.Project) error {
+	if err := validateProject(p); err != nil {
+		return err
+	}
+	w.Resume.Projects = append(w.Resume.Projects, p)
+	return nil
+}
+
+// AddPending adds the current step's pending entry and clears it. Like the
+// Add methods it fails when the entry is incomplete, e.g. left empty.
+func (w *Wizard) AddPending() error {
+	return w.addPending(w.Step(), true)
+}
+
+// addPending adds the pending entry of step s. Unless force is set, an
+// untouched entry is skipped and a problem names the unfinished entry.
+func (w *Wizard) addPending(s Step, force bool) error {
+	var pending any
+	var add func() error
+	switch s {
+	case StepEducation:
+		pending, add = &w.PendingEducation, func() error { return w.AddEducation(w.PendingEducation) }
+	case StepExperience:
+		pending, add = &w.PendingExperience, func() error { return w.AddExperience(w.PendingExperience) }
+	case StepProjects:
+		pending, add = &w.PendingProject, func() error { return w.AddProject(w.PendingProject) }
+	default:
+		return nil
+	}
+	entry := reflect.ValueOf(pending).Elem()
+	if !force && entry.IsZero() {
+		return nil
+	}
+	if err := add(); err != nil {
+		if force {
+			return err
+		}
+		return fmt.Errorf("the %s entry you started is not added yet: %w", strings.ToLower(s.Title()), err)
+	}
+	entry.SetZero()
+	return nil
+}
+
+// RemoveEducation removes the i-th education entry.
+func (w *Wizard) RemoveEducation(i int) {
+	w.Resume.Education = config.RemoveAt(w.Resume.Education, i)
+}
+
+// RemoveExperience removes the i-th experience entry.
+func (w *Wizard) RemoveExperience(i int) {
+	w.Resume.Experience = config.RemoveAt(w.Resume.Experience, i)
+}
+
+// RemoveProject removes the i-th project entry.
+func (w *Wizard) RemoveProject(i int) {
+	w.Resume.Projects = config.RemoveAt(w.Resume.Projects, i)
+}
+
+// SetSkills replaces the skills list from comma or newline separated input.
+func (w *Wizard) SetSkills(input string) {
+	w.Resume.Skills = config.SplitList(input)
+}
+
+// Finish adds any pending entries, validates the whole resume and writes it
+// to config.ResumePath. It returns the saved config.
+func (w *Wizard) Finish() (*config.Config, error) {
+	for _, s := range steps {
+		if err := w.addPending(s, false); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.ValidateAll(); err != nil {
+		return nil, err
+	}
+	cfg := &config.Config{Resume: w.Resume}
+	if err := cfg.CreateConfig(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,128 @@
+package setup
+
+import (
+	"reflect"
+	"testing"
+
+	"covlet/pkg/config"
+)
+
+func TestWizard_NavigationValidatesCurrentStep(t *testing.T) {
+	w := New()
+	if !w.IsFirst() || w.Step() != StepContact {
+		t.Fatalf("expected to start at contact step, got %v", w.Step())
+	}
+	if err := w.Next(); err == nil {
+		t.Fatalf("expected missing name to block the contact step")
+	}
+	w.Resume.Name = "Jane Doe"
+	w.Resume.Email = "not-an-email"
+	if err := w.Next(); err == nil {
+		t.Fatalf("expected invalid email to block the contact step")
+	}
+	w.Resume.Email = "jane@example.com"
+	if err := w.Next(); err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if w.Step() != StepEducation {
+		t.Fatalf("expected education step, got %v", w.Step())
+	}
+	if err := w.AddEducation(config.Education{Degree: "B.S."}); err == nil {
+		t.Fatalf("expected adding an education entry without institution to fail")
+	}
+	if len(w.Resume.Education) != 0 {
+		t.Fatalf("incomplete entry was added: %+v", w.Resume.Education)
+	}
+	w.Resume.Education = append(w.Resume.Education, config.Education{Degree: "B.S."})
+	if err := w.Next(); err == nil {
+		t.Fatalf("expected education entry without institution to fail")
+	}
+	w.RemoveEducation(0)
+	if err := w.Next(); err != nil {
+		t.Fatalf("expected the step to pass once the entry is removed: %v", err)
+	}
+	w.Back()
+	w.Back()
+	if w.Step() != StepContact {
+		t.Fatalf("expected Back to return to contact step, got %v", w.Step())
+	}
+}
+
+func TestWizard_FinishWritesValuesFile(t *testing.T) {
+	if err := config.SetMainDir(t.TempDir()); err != nil {
+		t.Fatalf("SetMainDir: %v", err)
+	}
+	w := New()
+	w.Resume.Name = "Jane Doe"
+	w.Resume.Email = "jane@example.com"
+	if err := w.AddExperience(config.Experience{Company: "Go Corp", Position: "Engineer"}); err != nil {
+		t.Fatalf("AddExperience: %v", err)
+	}
+	w.SetSkills("Go, SQL\nDocker")
+	for !w.IsLast() {
+		if err := w.Next(); err != nil {
+			t.Fatalf("Next at %v: %v", w.Step(), err)
+		}
+	}
+	if _, err := w.Finish(); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	cfg, err := config.LoadConfig(config.ResumePath())
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	got := cfg.Resume
+	if got.Name != "Jane Doe" || got.Email != "jane@example.com" {
+		t.Fatalf("unexpected contact info: %+v", got)
+	}
+	if len(got.Experience) != 1 || got.Experience[0].Company != "Go Corp" {
+		t.Fatalf("unexpected experience: %+v", got.Experience)
+	}
+	if !reflect.DeepEqual(got.Skills, []string{"Go", "SQL", "Docker"}) {
+		t.Fatalf("unexpected skills: %v", got.Skills)
+	}
+}
+
+func TestWizard_PendingEntry(t *testing.T) {
+	if err := config.SetMainDir(t.TempDir()); err != nil {
+		t.Fatalf("SetMainDir: %v", err)
+	}
+	w := New()
+	w.Resume.Name = "Jane Doe"
+	w.Resume.Email = "jane@example.com"
+	if err := w.Next(); err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if err := w.AddPending(); err == nil {
+		t.Fatalf("expected adding an empty entry to fail")
+	}
+	if err := w.Next(); err != nil {
+		t.Fatalf("an untouched form should not block Next: %v", err)
+	}
+
+	w.PendingExperience = config.Experience{Company: "Go Corp"}
+	if err := w.Next(); err == nil || w.Step() != StepExperience {
+		t.Fatalf("expected a half filled entry to block Next, got %v at %v", err, w.Step())
+	}
+	w.PendingExperience.Position = "Engineer"
+	if err := w.Next(); err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if len(w.Resume.Experience) != 1 || w.Resume.Experience[0].Position != "Engineer" {
+		t.Fatalf("pending entry was not added: %+v", w.Resume.Experience)
+	}
+	if !reflect.DeepEqual(w.PendingExperience, config.Experience{}) {
+		t.Fatalf("pending entry was not cleared: %+v", w.PendingExperience)
+	}
+
+	if err := w.Next(); err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	w.PendingProject.Name = "covlet"
+	if _, err := w.Finish(); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	if len(w.Resume.Projects) != 1 || w.Resume.Projects[0].Name != "covlet" {
+		t.Fatalf("Finish did not add the pending project: %+v", w.Resume.Projects)
+	}
+}